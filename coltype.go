@@ -0,0 +1,334 @@
+package mogi
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	querypb "github.com/guregu/mogi/internal/proto/query"
+	"github.com/guregu/mogi/internal/sqltypes"
+)
+
+// columnType is the type of a result column, either declared with ColumnType or inferred from the data.
+type columnType struct {
+	typ      querypb.Type
+	scan     reflect.Type // nil means use the default for typ
+	nullable bool
+	nullOK   bool
+	length   int64
+	lenOK    bool
+	prec     int64
+	scale    int64
+	precOK   bool
+}
+
+// sqlTypes maps MySQL type names to their vitess types.
+var sqlTypes = map[string]querypb.Type{
+	"BOOL":       sqltypes.Int8,
+	"BOOLEAN":    sqltypes.Int8,
+	"TINYINT":    sqltypes.Int8,
+	"SMALLINT":   sqltypes.Int16,
+	"MEDIUMINT":  sqltypes.Int24,
+	"INT":        sqltypes.Int32,
+	"INTEGER":    sqltypes.Int32,
+	"BIGINT":     sqltypes.Int64,
+	"FLOAT":      sqltypes.Float32,
+	"DOUBLE":     sqltypes.Float64,
+	"REAL":       sqltypes.Float64,
+	"DECIMAL":    sqltypes.Decimal,
+	"DEC":        sqltypes.Decimal,
+	"NUMERIC":    sqltypes.Decimal,
+	"TIMESTAMP":  sqltypes.Timestamp,
+	"DATE":       sqltypes.Date,
+	"TIME":       sqltypes.Time,
+	"DATETIME":   sqltypes.Datetime,
+	"YEAR":       sqltypes.Year,
+	"CHAR":       sqltypes.Char,
+	"VARCHAR":    sqltypes.VarChar,
+	"BINARY":     sqltypes.Binary,
+	"VARBINARY":  sqltypes.VarBinary,
+	"TINYTEXT":   sqltypes.Text,
+	"TEXT":       sqltypes.Text,
+	"MEDIUMTEXT": sqltypes.Text,
+	"LONGTEXT":   sqltypes.Text,
+	"TINYBLOB":   sqltypes.Blob,
+	"BLOB":       sqltypes.Blob,
+	"MEDIUMBLOB": sqltypes.Blob,
+	"LONGBLOB":   sqltypes.Blob,
+	"BIT":        sqltypes.Bit,
+	"ENUM":       sqltypes.Enum,
+	"SET":        sqltypes.Set,
+}
+
+var unsignedTypes = map[querypb.Type]querypb.Type{
+	sqltypes.Int8:  sqltypes.Uint8,
+	sqltypes.Int16: sqltypes.Uint16,
+	sqltypes.Int24: sqltypes.Uint24,
+	sqltypes.Int32: sqltypes.Uint32,
+	sqltypes.Int64: sqltypes.Uint64,
+}
+
+// mysqlTypeNames maps MySQL protocol type codes to their names.
+var mysqlTypeNames = map[int64]string{
+	1:   "TINYINT",
+	2:   "SMALLINT",
+	3:   "INT",
+	4:   "FLOAT",
+	5:   "DOUBLE",
+	6:   "NULL",
+	7:   "TIMESTAMP",
+	8:   "BIGINT",
+	9:   "MEDIUMINT",
+	10:  "DATE",
+	11:  "TIME",
+	12:  "DATETIME",
+	13:  "YEAR",
+	16:  "BIT",
+	246: "DECIMAL",
+	252: "TEXT",
+	253: "VARCHAR",
+	254: "CHAR",
+}
+
+// MySQL column flags, as returned by TypeToMySQL
+const (
+	mysqlFlagUnsigned = 32
+	mysqlFlagBinary   = 128
+	mysqlFlagEnum     = 256
+	mysqlFlagSet      = 2048
+)
+
+// parseColumnType parses a column declaration like "VARCHAR(255) NOT NULL" or "DECIMAL(10,2) UNSIGNED".
+func parseColumnType(decl string) (columnType, error) {
+	s := strings.ToUpper(strings.TrimSpace(decl))
+	ct := columnType{nullable: true, nullOK: true}
+	if strings.HasSuffix(s, "NOT NULL") {
+		ct.nullable = false
+		s = strings.TrimSpace(strings.TrimSuffix(s, "NOT NULL"))
+	} else if strings.HasSuffix(s, " NULL") {
+		s = strings.TrimSpace(strings.TrimSuffix(s, " NULL"))
+	}
+	unsigned := false
+	if strings.HasSuffix(s, " UNSIGNED") {
+		unsigned = true
+		s = strings.TrimSpace(strings.TrimSuffix(s, " UNSIGNED"))
+	}
+
+	name, args := s, ""
+	if i := strings.IndexByte(s, '('); i != -1 {
+		if !strings.HasSuffix(s, ")") {
+			return ct, fmt.Errorf("mogi: bad column type %q", decl)
+		}
+		name, args = strings.TrimSpace(s[:i]), s[i+1:len(s)-1]
+	}
+	typ, ok := sqlTypes[name]
+	if !ok {
+		return ct, fmt.Errorf("mogi: unknown column type %q", decl)
+	}
+	if unsigned {
+		if u, ok := unsignedTypes[typ]; ok {
+			typ = u
+		} else if !sqltypes.IsFloat(typ) && typ != sqltypes.Decimal {
+			return ct, fmt.Errorf("mogi: %s can't be UNSIGNED", name)
+		}
+	}
+	ct.typ = typ
+
+	if args == "" {
+		switch typ {
+		case sqltypes.Char, sqltypes.Binary:
+			ct.length, ct.lenOK = 1, true
+		case sqltypes.Text, sqltypes.Blob:
+			ct.length, ct.lenOK = textLength(name), true
+		}
+		return ct, nil
+	}
+	if typ == sqltypes.Enum || typ == sqltypes.Set {
+		// the arguments are the allowed values
+		return ct, nil
+	}
+	var nums []int64
+	for _, arg := range strings.Split(args, ",") {
+		n, err := strconv.ParseInt(strings.TrimSpace(arg), 10, 64)
+		if err != nil {
+			return ct, fmt.Errorf("mogi: bad column type %q: %v", decl, err)
+		}
+		nums = append(nums, n)
+	}
+	switch {
+	case typ == sqltypes.Decimal || sqltypes.IsFloat(typ):
+		ct.prec, ct.precOK = nums[0], true
+		if len(nums) > 1 {
+			ct.scale = nums[1]
+		}
+	case sqltypes.IsText(typ) || sqltypes.IsBinary(typ):
+		ct.length, ct.lenOK = nums[0], true
+	}
+	return ct, nil
+}
+
+// newColumnTypes returns a copy of types with col declared as decl.
+// If decl is invalid, it returns types and the error.
+func newColumnTypes(types map[string]columnType, col string, decl string) (map[string]columnType, error) {
+	ct, err := parseColumnType(decl)
+	if err != nil {
+		return types, err
+	}
+	cp := make(map[string]columnType, len(types)+1)
+	for k, v := range types {
+		cp[k] = v
+	}
+	cp[strings.ToLower(col)] = ct
+	return cp, nil
+}
+
+// textLength returns the maximum length of the TEXT and BLOB types.
+func textLength(name string) int64 {
+	switch {
+	case strings.HasPrefix(name, "TINY"):
+		return 1<<8 - 1
+	case strings.HasPrefix(name, "MEDIUM"):
+		return 1<<24 - 1
+	case strings.HasPrefix(name, "LONG"):
+		return 1<<32 - 1
+	}
+	return 1<<16 - 1
+}
+
+// inferColumnType guesses the type of column i from its values.
+func inferColumnType(data [][]driver.Value, i int) columnType {
+	ct := columnType{typ: sqltypes.Null}
+	for _, row := range data {
+		if i >= len(row) {
+			continue
+		}
+		v := row[i]
+		if v == nil {
+			ct.nullable, ct.nullOK = true, true
+			continue
+		}
+		if ct.scan != nil {
+			continue
+		}
+		ct.scan = reflect.TypeOf(v)
+		switch v.(type) {
+		case int64:
+			ct.typ = sqltypes.Int64
+		case float64:
+			ct.typ = sqltypes.Float64
+		case bool:
+			ct.typ = sqltypes.Int8
+		case string, []byte:
+			ct.typ = sqltypes.VarChar
+			ct.length, ct.lenOK = math.MaxInt64, true
+		case time.Time:
+			ct.typ = sqltypes.Datetime
+		default:
+			ct.typ = sqltypes.Blob
+		}
+	}
+	if ct.nullable && ct.scan != nil {
+		ct.scan = nullScanType(ct.scan)
+	}
+	return ct
+}
+
+// nullScanType returns the sql.Null* type for scanning NULLs in a column of type scan.
+func nullScanType(scan reflect.Type) reflect.Type {
+	switch scan {
+	case scanTypeInt64:
+		return scanTypeNullInt
+	case scanTypeFloat64:
+		return scanTypeNullFloat
+	case scanTypeTime:
+		return scanTypeNullTime
+	case scanTypeString:
+		return scanTypeNullStr
+	case scanTypeBool:
+		return scanTypeNullBool
+	case scanTypeBytes:
+		return scanTypeRawBytes
+	}
+	return scanTypeUnknown
+}
+
+// databaseTypeName returns the MySQL name of typ, such as "VARCHAR" or "UNSIGNED BIGINT".
+func databaseTypeName(typ querypb.Type) string {
+	mysqlType, flags := sqltypes.TypeToMySQL(typ)
+	name := mysqlTypeNames[mysqlType]
+	switch {
+	case flags&mysqlFlagEnum != 0:
+		return "ENUM"
+	case flags&mysqlFlagSet != 0:
+		return "SET"
+	case flags&mysqlFlagBinary != 0 && sqltypes.IsBinary(typ):
+		switch mysqlType {
+		case 252:
+			return "BLOB"
+		case 253:
+			return "VARBINARY"
+		case 254:
+			return "BINARY"
+		}
+	case flags&mysqlFlagUnsigned != 0 && sqltypes.IsIntegral(typ) && typ != sqltypes.Year:
+		return "UNSIGNED " + name
+	}
+	return name
+}
+
+var (
+	scanTypeInt64     = reflect.TypeOf(int64(0))
+	scanTypeUint64    = reflect.TypeOf(uint64(0))
+	scanTypeFloat64   = reflect.TypeOf(float64(0))
+	scanTypeTime      = reflect.TypeOf(time.Time{})
+	scanTypeString    = reflect.TypeOf("")
+	scanTypeBool      = reflect.TypeOf(false)
+	scanTypeBytes     = reflect.TypeOf([]byte{})
+	scanTypeNullInt   = reflect.TypeOf(sql.NullInt64{})
+	scanTypeNullFloat = reflect.TypeOf(sql.NullFloat64{})
+	scanTypeNullTime  = reflect.TypeOf(sql.NullTime{})
+	scanTypeNullStr   = reflect.TypeOf(sql.NullString{})
+	scanTypeNullBool  = reflect.TypeOf(sql.NullBool{})
+	scanTypeRawBytes  = reflect.TypeOf(sql.RawBytes{})
+	scanTypeUnknown   = reflect.TypeOf(new(interface{})).Elem()
+)
+
+// scanType returns the Go type suitable for scanning this column.
+func (ct columnType) scanType() reflect.Type {
+	if ct.scan != nil {
+		return ct.scan
+	}
+	typ := ct.typ
+	switch {
+	case typ == sqltypes.Null:
+		return scanTypeUnknown
+	case typ == sqltypes.Year || typ == sqltypes.Bit:
+		return scanTypeRawBytes
+	case sqltypes.IsUnsigned(typ):
+		if ct.nullable {
+			return scanTypeNullInt
+		}
+		return scanTypeUint64
+	case sqltypes.IsSigned(typ):
+		if ct.nullable {
+			return scanTypeNullInt
+		}
+		return scanTypeInt64
+	case sqltypes.IsFloat(typ):
+		if ct.nullable {
+			return scanTypeNullFloat
+		}
+		return scanTypeFloat64
+	case typ == sqltypes.Timestamp || typ == sqltypes.Date || typ == sqltypes.Datetime:
+		if ct.nullable {
+			return scanTypeNullTime
+		}
+		return scanTypeTime
+	}
+	return scanTypeRawBytes
+}
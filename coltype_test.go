@@ -0,0 +1,120 @@
+package mogi_test
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/guregu/mogi"
+)
+
+func TestColumnTypes(t *testing.T) {
+	defer mogi.Reset()
+	db := openDB()
+
+	mogi.Select().From("beer").
+		ColumnType("id", "BIGINT UNSIGNED NOT NULL").
+		ColumnType("name", "varchar(255)").
+		ColumnType("pct", "DECIMAL(3,1)").
+		StubCSV(beerCSV)
+
+	rows, err := db.Query("SELECT id, name, brewery, pct FROM beer")
+	checkNil(t, err)
+	defer rows.Close()
+	types, err := rows.ColumnTypes()
+	checkNil(t, err)
+
+	expect := []struct {
+		name     string
+		scan     reflect.Type
+		nullable bool
+		nullOK   bool
+		length   int64
+		prec     int64
+		scale    int64
+	}{
+		{"UNSIGNED BIGINT", reflect.TypeOf(uint64(0)), false, true, 0, 0, 0},
+		{"VARCHAR", reflect.TypeOf(sql.RawBytes{}), true, true, 255, 0, 0},
+		// inferred
+		{"VARCHAR", reflect.TypeOf([]byte{}), false, false, 1<<63 - 1, 0, 0},
+		{"DECIMAL", reflect.TypeOf(sql.RawBytes{}), true, true, 0, 3, 1},
+	}
+	for i, ct := range types {
+		exp := expect[i]
+		if ct.DatabaseTypeName() != exp.name {
+			t.Error(ct.Name(), "type name should be", exp.name, "but is", ct.DatabaseTypeName())
+		}
+		if ct.ScanType() != exp.scan {
+			t.Error(ct.Name(), "scan type should be", exp.scan, "but is", ct.ScanType())
+		}
+		if nullable, ok := ct.Nullable(); nullable != exp.nullable || ok != exp.nullOK {
+			t.Error(ct.Name(), "bad nullable:", nullable, ok)
+		}
+		if length, _ := ct.Length(); length != exp.length {
+			t.Error(ct.Name(), "length should be", exp.length, "but is", length)
+		}
+		if prec, scale, _ := ct.DecimalSize(); prec != exp.prec || scale != exp.scale {
+			t.Error(ct.Name(), "bad precision and scale:", prec, scale)
+		}
+	}
+}
+
+func TestColumnTypesInferred(t *testing.T) {
+	defer mogi.Reset()
+	db := openDB()
+
+	now := time.Now()
+	mogi.Select().Stub([][]driver.Value{
+		{int64(1), nil, 5.5, now, true},
+		{int64(2), "Punk IPA", nil, now, false},
+	})
+	rows, err := db.Query("SELECT id, name, pct, brewed_at, seasonal FROM beer")
+	checkNil(t, err)
+	defer rows.Close()
+	types, err := rows.ColumnTypes()
+	checkNil(t, err)
+
+	names := []string{"BIGINT", "VARCHAR", "DOUBLE", "DATETIME", "TINYINT"}
+	nullable := []bool{false, true, true, false, false}
+	for i, ct := range types {
+		if ct.DatabaseTypeName() != names[i] {
+			t.Error(ct.Name(), "type name should be", names[i], "but is", ct.DatabaseTypeName())
+		}
+		if null, _ := ct.Nullable(); null != nullable[i] {
+			t.Error(ct.Name(), "nullable should be", nullable[i], "but is", null)
+		}
+	}
+	// nullable columns scan into sql.Null* types
+	scans := []reflect.Type{
+		reflect.TypeOf(int64(0)),
+		reflect.TypeOf(sql.NullString{}),
+		reflect.TypeOf(sql.NullFloat64{}),
+		reflect.TypeOf(time.Time{}),
+		reflect.TypeOf(false),
+	}
+	for i, ct := range types {
+		if ct.ScanType() != scans[i] {
+			t.Error(ct.Name(), "scan type should be", scans[i], "but is", ct.ScanType())
+		}
+	}
+}
+
+func TestColumnTypeInvalid(t *testing.T) {
+	defer mogi.Reset()
+	db := openDB()
+
+	mogi.Select().ColumnType("id", "BIGGESTINT").StubCSV(beerCSV)
+	_, err := db.Query("SELECT id FROM beer")
+	if err == nil || !strings.Contains(err.Error(), "BIGGESTINT") {
+		t.Error("expected an error for an unknown type, got:", err)
+	}
+
+	mogi.Insert().ColumnType("id", "BIGGESTINT").StubResult(1, 1)
+	_, err = db.Exec("INSERT INTO beer (name) VALUES (?)", "Mikkeller")
+	if err == nil || !strings.Contains(err.Error(), "BIGGESTINT") {
+		t.Error("expected an error for an unknown type, got:", err)
+	}
+}
@@ -0,0 +1,162 @@
+package mogi_test
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/guregu/mogi"
+)
+
+// These tests are meant to be run with the race detector: go test -race
+
+func TestConcurrentQueries(t *testing.T) {
+	reg := mogi.New("concurrent-queries")
+	defer reg.Reset()
+	db, _ := sql.Open("mogi", "concurrent-queries")
+
+	reg.Select().From("beer").Where("pct", 5).StubCSV(beerCSV)
+	reg.Select().From("beer").Args(5).Priority(-1).StubCSV(beerCSV)
+	reg.Insert().Into("beer").Value("name", "Mikkel’s Dream").StubResult(3, 1)
+	reg.Update().Table("beer").Where("id", 3).StubRowsAffected(1)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 20; j++ {
+				runBeerSelectQuery(t, db)
+				_, err := db.Exec("INSERT INTO beer (name, brewery, pct) VALUES (?, ?, ?)", "Mikkel’s Dream", "Mikkeller", 4.6)
+				checkNil(t, err)
+				_, err = db.Exec("UPDATE beer SET pct = ? WHERE id = ?", 4.7, 3)
+				checkNil(t, err)
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestConcurrentStubbing(t *testing.T) {
+	reg := mogi.New("concurrent-stubbing")
+	defer reg.Reset()
+	db, _ := sql.Open("mogi", "concurrent-stubbing")
+
+	reg.Select().Priority(-100).StubCSV(beerCSV)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(2)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 20; j++ {
+				reg.Select("id", "name", "brewery", "pct").Where("id", i*100+j).StubCSV(beerCSV)
+				reg.Delete().Table("beer").Where("id", i*100+j).StubRowsAffected(1)
+			}
+		}(i)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 20; j++ {
+				rows, err := db.Query("SELECT id, name, brewery, pct FROM beer WHERE id = ?", i*100+j)
+				checkNil(t, err)
+				if rows != nil {
+					rows.Close()
+				}
+				_, err = db.Exec("DELETE FROM beer WHERE id = ?", i*100+j)
+				if err != nil && !errors.Is(err, mogi.ErrUnstubbed) {
+					t.Error("unexpected error", err)
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+}
+
+func TestConcurrentReset(t *testing.T) {
+	reg := mogi.New("concurrent-reset")
+	defer reg.Reset()
+	db, _ := sql.Open("mogi", "concurrent-reset")
+
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for {
+			select {
+			case <-done:
+				return
+			default:
+			}
+			reg.Select().StubCSV(beerCSV)
+			reg.Reset()
+		}
+	}()
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 50; j++ {
+				rows, err := db.Query("SELECT id, name, brewery, pct FROM beer WHERE pct > ?", 5)
+				if err != nil && !errors.Is(err, mogi.ErrUnstubbed) {
+					t.Error("unexpected error", err)
+				}
+				if rows != nil {
+					rows.Close()
+				}
+			}
+		}()
+	}
+	time.Sleep(10 * time.Millisecond)
+	close(done)
+	wg.Wait()
+}
+
+func TestConcurrentRegistries(t *testing.T) {
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			dsn := fmt.Sprintf("concurrent-registry-%d", i)
+			reg := mogi.New(dsn)
+			defer reg.Reset()
+			db, _ := sql.Open("mogi", dsn)
+			reg.Select().Where("pct", 5).StubCSV(beerCSV)
+			runBeerSelectQuery(t, db)
+		}(i)
+	}
+	wg.Wait()
+}
+
+func TestConcurrentSettings(t *testing.T) {
+	defer mogi.Reset()
+	defer mogi.ParseTime("")
+	db := openDB()
+	mogi.Select().StubCSV(beerCSV)
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 20; i++ {
+			mogi.ParseTime(time.RFC3339)
+			mogi.ParseTime("")
+			mogi.Verbose(false)
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 20; i++ {
+			rows, err := db.Query("SELECT id, name, brewery, pct FROM beer WHERE pct > ?", 5)
+			checkNil(t, err)
+			if rows != nil {
+				rows.Close()
+			}
+		}
+	}()
+	wg.Wait()
+}
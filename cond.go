@@ -12,14 +12,19 @@ import (
 )
 
 type cond interface {
-	matches(in input) bool
+	matches(in *input) bool
 	priority() int
 	fmt.Stringer
 }
 
 type condchain []cond
 
-func (chain condchain) matches(in input) bool {
+// invalidCond is implemented by conds that can be built from bad input, such as a malformed expression.
+type invalidCond interface {
+	invalid() error
+}
+
+func (chain condchain) matches(in *input) bool {
 	for _, c := range chain {
 		if !c.matches(in) {
 			return false
@@ -28,6 +33,42 @@ func (chain condchain) matches(in input) bool {
 	return true
 }
 
+// matchesQuietly is like matches, but skips conds with side effects (Notify and Dump).
+func (chain condchain) matchesQuietly(in *input) bool {
+	for _, c := range chain {
+		switch c.(type) {
+		case notifyCond, dumpCond:
+			continue
+		}
+		if !c.matches(in) {
+			return false
+		}
+	}
+	return true
+}
+
+// invalid returns the error of the first invalid cond of the chain, if the rest of the chain matches.
+// Stubs with invalid conds never match, but the queries they would have matched return this error.
+func (chain condchain) invalid(in *input) error {
+	var err error
+	for _, c := range chain {
+		if ic, ok := c.(invalidCond); ok && ic.invalid() != nil {
+			if err == nil {
+				err = ic.invalid()
+			}
+			continue
+		}
+		switch c.(type) {
+		case notifyCond, dumpCond:
+			continue
+		}
+		if !c.matches(in) {
+			return nil
+		}
+	}
+	return err
+}
+
 func (chain condchain) priority() int {
 	p := 0
 	for _, c := range chain {
@@ -37,14 +78,22 @@ func (chain condchain) priority() int {
 }
 
 func (chain condchain) String() string {
-	return "Chain..."
+	strs := make([]string, 0, len(chain))
+	for _, c := range chain {
+		switch c.(type) {
+		case priorityCond, notifyCond, dumpCond:
+			continue
+		}
+		strs = append(strs, c.String())
+	}
+	return strings.Join(strs, " ")
 }
 
 type tableCond struct {
 	table string
 }
 
-func (tc tableCond) matches(in input) bool {
+func (tc tableCond) matches(in *input) bool {
 	switch x := in.statement.(type) {
 	case *sqlparser.Insert:
 		return strings.ToLower(tc.table) == strings.ToLower(string(x.Table.Name))
@@ -68,9 +117,14 @@ type argsCond struct {
 	args []driver.Value
 }
 
-func (ac argsCond) matches(in input) bool {
-	given := unifyValues(ac.args)
-	return reflect.DeepEqual(given, in.args)
+func newArgsCond(args []driver.Value) argsCond {
+	return argsCond{
+		args: unifyValues(args),
+	}
+}
+
+func (ac argsCond) matches(in *input) bool {
+	return reflect.DeepEqual(ac.args, in.args)
 }
 
 func (ac argsCond) priority() int {
@@ -81,6 +135,34 @@ func (ac argsCond) String() string {
 	return fmt.Sprintf("WITH ARGS %+v", ac.args)
 }
 
+type argNamedCond struct {
+	name string
+	v    interface{}
+}
+
+func newArgNamedCond(name string, v interface{}) argNamedCond {
+	return argNamedCond{
+		name: name,
+		v:    unify(v),
+	}
+}
+
+func (nc argNamedCond) matches(in *input) bool {
+	v, ok := in.named[nc.name]
+	if !ok {
+		return false
+	}
+	return equals(unify(v), nc.v)
+}
+
+func (nc argNamedCond) priority() int {
+	return 1
+}
+
+func (nc argNamedCond) String() string {
+	return fmt.Sprintf("WITH ARG %s ≈ %v", nc.name, nc.v)
+}
+
 type valueCond struct {
 	row int
 	col string
@@ -95,7 +177,7 @@ func newValueCond(row int, col string, v interface{}) valueCond {
 	}
 }
 
-func (vc valueCond) matches(in input) bool {
+func (vc valueCond) matches(in *input) bool {
 	switch in.statement.(type) {
 	case *sqlparser.Insert:
 		values := in.rows()
@@ -130,7 +212,7 @@ type priorityCond struct {
 	p int
 }
 
-func (pc priorityCond) matches(in input) bool {
+func (pc priorityCond) matches(in *input) bool {
 	return true
 }
 
@@ -146,7 +228,7 @@ type notifyCond struct {
 	ch chan<- struct{}
 }
 
-func (nc notifyCond) matches(in input) bool {
+func (nc notifyCond) matches(in *input) bool {
 	go func() {
 		nc.ch <- struct{}{}
 	}()
@@ -163,7 +245,7 @@ func (nc notifyCond) String() string {
 
 type dumpCond struct{}
 
-func (dc dumpCond) matches(in input) bool {
+func (dc dumpCond) matches(in *input) bool {
 	fmt.Println(in.query)
 	spew.Dump(in.args)
 	switch in.statement.(type) {
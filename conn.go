@@ -1,73 +1,204 @@
 package mogi
 
 import (
+	"context"
 	"log"
-	"sort"
 
 	"database/sql/driver"
+
+	"github.com/guregu/mogi/internal/sqlparser"
 )
 
 type conn struct {
-	stubs     stubs
-	execStubs execStubs
-}
-
-func newConn() *conn {
-	return &conn{}
-}
-
-func addStub(s *Stub) {
-	drv.conn.stubs = append(drv.conn.stubs, s)
-	sort.Sort(drv.conn.stubs)
+	reg *Registry
+	tx  *tx
 }
 
-func addExecStub(s *ExecStub) {
-	drv.conn.execStubs = append(drv.conn.execStubs, s)
-	sort.Sort(drv.conn.execStubs)
+func newConn(reg *Registry) *conn {
+	return &conn{
+		reg: reg,
+	}
 }
 
 func (c *conn) Prepare(query string) (driver.Stmt, error) {
 	return &stmt{
+		conn:  c,
 		query: query,
 	}, nil
 }
 
+func (c *conn) PrepareContext(ctx context.Context, query string) (driver.Stmt, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return c.Prepare(query)
+}
+
 func (c *conn) Close() error {
 	return nil
 }
 
 func (c *conn) Begin() (driver.Tx, error) {
-	return &tx{}, nil
+	return c.BeginTx(context.Background(), driver.TxOptions{})
+}
+
+func (c *conn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	c.tx = &tx{
+		conn: c,
+		opts: opts,
+	}
+	return c.tx, nil
+}
+
+func (c *conn) Ping(ctx context.Context) error {
+	return ctx.Err()
 }
 
 func (c *conn) Query(query string, args []driver.Value) (driver.Rows, error) {
-	in, err := newInput(query, args)
+	return c.QueryContext(context.Background(), query, valuesToNamedValues(args))
+}
+
+func (c *conn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	in, err := c.newInput(query, args)
 	if err != nil {
+		c.reg.addRecord(newRecord(in))
 		return nil, err
 	}
-	for _, c := range c.stubs {
-		if c.matches(in) {
-			return c.rows(in)
+	switch in.statement.(type) {
+	case *sqlparser.Insert, *sqlparser.Update, *sqlparser.Delete:
+		// INSERT ... RETURNING and friends
+		s, err := c.matchExecStub(ctx, in)
+		if err != nil {
+			return nil, err
 		}
+		return s.rows(in)
 	}
-	if verbose {
-		log.Println("Unstubbed query:", query, args)
+	s, err := c.matchStub(ctx, in)
+	if err != nil {
+		return nil, err
 	}
-	return nil, ErrUnstubbed
+	return s.rows(in)
 }
 
 func (c *conn) Exec(query string, args []driver.Value) (driver.Result, error) {
-	in, err := newInput(query, args)
+	return c.ExecContext(context.Background(), query, valuesToNamedValues(args))
+}
+
+func (c *conn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	in, err := c.newInput(query, args)
+	if err != nil {
+		c.reg.addRecord(newRecord(in))
+		return nil, err
+	}
+	s, err := c.matchExecStub(ctx, in)
+	if err != nil {
+		return nil, err
+	}
+	return s.results(in)
+}
+
+// querier answers queries: a Stub, ExecStub (for RETURNING), or TableStub.
+type querier interface {
+	rows(in *input) (*rows, error)
+}
+
+// execer answers execs, and queries with a RETURNING clause: an ExecStub or TableStub.
+type execer interface {
+	querier
+	results(in *input) (driver.Result, error)
+}
+
+// matchStub finds the query stub for in, recording it in the history and waiting for its delay.
+// If no stub matches, the query's table is used instead.
+func (c *conn) matchStub(ctx context.Context, in *input) (querier, error) {
+	s, err := c.reg.matchStub(in)
+	rec := newRecord(in)
+	rec.Stub = s
+	if s == nil && err == nil {
+		if t := c.reg.table(in); t != nil {
+			rec.Table = t
+			c.reg.addRecord(rec)
+			return t, nil
+		}
+	}
+	c.reg.addRecord(rec)
 	if err != nil {
 		return nil, err
 	}
-	for _, c := range c.execStubs {
-		if c.matches(in) {
-			return c.results()
+	if s == nil {
+		uerr := c.reg.unstubbed(in)
+		if isVerbose() {
+			log.Println("Unstubbed query:", uerr)
+		}
+		return nil, uerr
+	}
+	if err := s.delay.wait(ctx); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// matchExecStub finds the exec stub for in, recording it in the history and waiting for its delay.
+// If no stub matches, the query's table is used instead.
+func (c *conn) matchExecStub(ctx context.Context, in *input) (execer, error) {
+	s, err := c.reg.matchExecStub(in)
+	rec := newRecord(in)
+	rec.ExecStub = s
+	if s == nil && err == nil {
+		if t := c.reg.table(in); t != nil {
+			rec.Table = t
+			c.reg.addRecord(rec)
+			return t, nil
 		}
 	}
-	if verbose {
-		log.Println("Unstubbed query:", query, args)
+	c.reg.addRecord(rec)
+	if err != nil {
+		return nil, err
+	}
+	if s == nil {
+		uerr := c.reg.execUnstubbed(in)
+		if isVerbose() {
+			log.Println("Unstubbed query:", uerr)
+		}
+		return nil, uerr
+	}
+	if err := s.delay.wait(ctx); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// CheckNamedValue accepts named args (sql.Named), converting values like the default converter.
+func (c *conn) CheckNamedValue(nv *driver.NamedValue) (err error) {
+	nv.Value, err = driver.DefaultParameterConverter.ConvertValue(nv.Value)
+	return err
+}
+
+func (c *conn) newInput(query string, args []driver.NamedValue) (*input, error) {
+	in, err := newInput(c.reg.Dialect(), query, args)
+	if c.tx != nil {
+		opts := c.tx.opts
+		in.tx = &opts
+	}
+	return in, err
+}
+
+func valuesToNamedValues(args []driver.Value) []driver.NamedValue {
+	named := make([]driver.NamedValue, len(args))
+	for i, arg := range args {
+		named[i] = driver.NamedValue{
+			Ordinal: i + 1,
+			Value:   arg,
+		}
 	}
-	return nil, ErrUnstubbed
+	return named
 }
@@ -0,0 +1,37 @@
+package mogi
+
+import (
+	"context"
+	"math/rand"
+	"time"
+)
+
+// latency simulates slow queries.
+type latency struct {
+	delay  time.Duration
+	jitter time.Duration
+}
+
+func (l latency) duration() time.Duration {
+	d := l.delay
+	if l.jitter > 0 {
+		d += time.Duration(rand.Int63n(int64(l.jitter)))
+	}
+	return d
+}
+
+// wait sleeps for the delay, returning early with ctx.Err() if ctx is done.
+func (l latency) wait(ctx context.Context) error {
+	d := l.duration()
+	if d <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
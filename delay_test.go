@@ -0,0 +1,56 @@
+package mogi_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/guregu/mogi"
+)
+
+func TestDelay(t *testing.T) {
+	defer mogi.Reset()
+	db := openDB()
+
+	mogi.Select().From("beer").Delay(20 * time.Millisecond).StubCSV(beerCSV)
+	start := time.Now()
+	runBeerSelectQuery(t, db)
+	if elapsed := time.Since(start); elapsed < 20*time.Millisecond {
+		t.Error("query should have been delayed, but took", elapsed)
+	}
+
+	mogi.Insert().Into("beer").DelayJitter(10*time.Millisecond, 10*time.Millisecond).StubResult(3, 1)
+	start = time.Now()
+	_, err := db.Exec("INSERT INTO beer (name, brewery, pct) VALUES (?, ?, ?)", "Mikkel’s Dream", "Mikkeller", 4.6)
+	checkNil(t, err)
+	if elapsed := time.Since(start); elapsed < 10*time.Millisecond {
+		t.Error("exec should have been delayed, but took", elapsed)
+	}
+}
+
+func TestDelayTimeout(t *testing.T) {
+	defer mogi.Reset()
+	db := openDB()
+
+	mogi.Select().From("beer").Delay(time.Minute).StubCSV(beerCSV)
+	mogi.Update().Table("beer").Delay(time.Minute).StubRowsAffected(1)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	_, err := db.QueryContext(ctx, "SELECT id, name, brewery, pct FROM beer WHERE pct > ?", 5)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Error("err should be DeadlineExceeded but is", err)
+	}
+	if elapsed := time.Since(start); elapsed > 10*time.Second {
+		t.Error("query should have been aborted, but took", elapsed)
+	}
+
+	ctx, cancel = context.WithCancel(context.Background())
+	time.AfterFunc(10*time.Millisecond, cancel)
+	_, err = db.ExecContext(ctx, "UPDATE beer SET pct = ? WHERE id = ?", 4.7, 3)
+	if !errors.Is(err, context.Canceled) {
+		t.Error("err should be Canceled but is", err)
+	}
+}
@@ -6,7 +6,7 @@ import (
 
 type deleteCond struct{}
 
-func (uc deleteCond) matches(in input) bool {
+func (uc deleteCond) matches(in *input) bool {
 	_, ok := in.statement.(*sqlparser.Delete)
 	return ok
 }
@@ -1,6 +1,7 @@
 package mogi_test
 
 import (
+	"errors"
 	"testing"
 
 	"github.com/guregu/mogi"
@@ -37,7 +38,7 @@ func TestDelete(t *testing.T) {
 	mogi.Reset()
 	mogi.Delete().Table("beer").Where("id", 50).StubRowsAffected(1)
 	_, err = db.Exec("DELETE FROM beer WHERE id = ?", 42)
-	if err != mogi.ErrUnstubbed {
+	if !errors.Is(err, mogi.ErrUnstubbed) {
 		t.Error("err should be ErrUnstubbed but is", err)
 	}
 }
@@ -0,0 +1,247 @@
+package mogi
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/guregu/mogi/internal/sqlparser"
+)
+
+// maxCandidates is how many of the closest stubs an UnstubbedError describes
+const maxCandidates = 3
+
+// UnstubbedError is returned for queries that didn't match any stubs.
+// It describes why the closest stubs didn't match.
+// errors.Is(err, ErrUnstubbed) is true for UnstubbedErrors.
+type UnstubbedError struct {
+	Query string
+	Args  []driver.Value
+	// Candidates are the stubs for the same kind of statement that came closest to matching, best first.
+	Candidates []Mismatch
+}
+
+// Mismatch describes a stub that didn't match a query.
+type Mismatch struct {
+	// Stub describes the stub's conditions.
+	Stub string
+	// Reasons explains each condition that failed.
+	Reasons []string
+}
+
+func (e *UnstubbedError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s: %s %v", ErrUnstubbed, e.Query, e.Args)
+	for i, c := range e.Candidates {
+		fmt.Fprintf(&b, "\n\tclosest stub #%d: %s", i+1, c.Stub)
+		for _, reason := range c.Reasons {
+			fmt.Fprintf(&b, "\n\t\t%s", reason)
+		}
+	}
+	return b.String()
+}
+
+// Is returns true for ErrUnstubbed.
+func (e *UnstubbedError) Is(target error) bool {
+	return target == ErrUnstubbed
+}
+
+// explainer is implemented by conds that can describe what they saw instead
+type explainer interface {
+	explain(in *input) string
+}
+
+// kinder is implemented by conds that match a kind of statement
+type kinder interface {
+	kind() string
+}
+
+type candidate struct {
+	Mismatch
+	failed   int
+	priority int
+}
+
+// diagnose explains why the stubs given by chains didn't match the input
+func diagnose(in *input, chains []condchain, counts []expectation) *UnstubbedError {
+	var candidates []candidate
+	for i, chain := range chains {
+		// only look at stubs for the same kind of statement
+		if len(chain) == 0 {
+			continue
+		}
+		if k, ok := chain[0].(kinder); ok && k.kind() != in.kind() {
+			continue
+		}
+		var reasons []string
+		for _, c := range chain {
+			switch c.(type) {
+			case priorityCond, notifyCond, dumpCond:
+				// these always match and might have side effects
+				continue
+			}
+			if c.matches(in) {
+				continue
+			}
+			reason := fmt.Sprintf("%s: didn't match", c)
+			if ex, ok := c.(explainer); ok {
+				reason = fmt.Sprintf("%s: got %s", c, ex.explain(in))
+			}
+			reasons = append(reasons, reason)
+		}
+		failed := len(reasons)
+		if failed == 0 && counts[i].usedUp() {
+			reasons = append(reasons, fmt.Sprintf("used up: %s", counts[i].problem()))
+		}
+		candidates = append(candidates, candidate{
+			Mismatch: Mismatch{
+				Stub:    chain.String(),
+				Reasons: reasons,
+			},
+			failed:   failed,
+			priority: chain.priority(),
+		})
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		if candidates[i].failed != candidates[j].failed {
+			return candidates[i].failed < candidates[j].failed
+		}
+		return candidates[i].priority > candidates[j].priority
+	})
+	if len(candidates) > maxCandidates {
+		candidates = candidates[:maxCandidates]
+	}
+
+	err := &UnstubbedError{
+		Query: in.query,
+		Args:  in.args,
+	}
+	for _, c := range candidates {
+		err.Candidates = append(err.Candidates, c.Mismatch)
+	}
+	return err
+}
+
+func (sc selectCond) kind() string {
+	return "SELECT"
+}
+
+func (ic insertCond) kind() string {
+	return "INSERT"
+}
+
+func (uc updateCond) kind() string {
+	return "UPDATE"
+}
+
+func (dc deleteCond) kind() string {
+	return "DELETE"
+}
+
+func (sc selectCond) explain(in *input) string {
+	return explainStrings(in.cols())
+}
+
+func (fc fromCond) explain(in *input) string {
+	return explainStrings(in.tables())
+}
+
+func (ic insertCond) explain(in *input) string {
+	return explainStrings(in.cols())
+}
+
+func (uc updateCond) explain(in *input) string {
+	return explainStrings(in.cols())
+}
+
+func (tc tableCond) explain(in *input) string {
+	return explainStrings(in.tables())
+}
+
+func (ac argsCond) explain(in *input) string {
+	return fmt.Sprintf("%v", in.args)
+}
+
+func (nc argNamedCond) explain(in *input) string {
+	v, ok := in.named[nc.name]
+	if !ok {
+		return fmt.Sprintf("no arg named %s", nc.name)
+	}
+	return fmt.Sprintf("%v", unify(v))
+}
+
+func (wc whereCond) explain(in *input) string {
+	v, ok := in.where()[wc.col]
+	if !ok {
+		return fmt.Sprintf("no %s in WHERE", wc.col)
+	}
+	return fmt.Sprintf("%v", v)
+}
+
+func (wc whereOpCond) explain(in *input) string {
+	var got []string
+	for k, v := range in.whereOp() {
+		if k.col != wc.col {
+			continue
+		}
+		if v == nil && strings.HasPrefix(k.op, "is ") {
+			got = append(got, fmt.Sprintf("%s %s", k.col, strings.ToUpper(k.op)))
+			continue
+		}
+		got = append(got, fmt.Sprintf("%s %s %v", k.col, strings.ToUpper(k.op), v))
+	}
+	if len(got) == 0 {
+		return fmt.Sprintf("no %s in WHERE", wc.col)
+	}
+	sort.Strings(got)
+	return strings.Join(got, ", ")
+}
+
+func (wc whereExprCond) explain(in *input) string {
+	if wc.err != nil {
+		return wc.err.Error()
+	}
+	w, _ := in.whereClause()
+	if w == nil {
+		return "no WHERE"
+	}
+	return canonicalWhere(in, w.Expr)
+}
+
+func (wc whereRowCond) explain(in *input) string {
+	w, _ := in.whereClause()
+	if w == nil {
+		return "no WHERE"
+	}
+	if _, err := in.satisfies(wc.cols, wc.row); err != nil {
+		return err.Error()
+	}
+	return sqlparser.String(w.Expr)
+}
+
+func (vc valueCond) explain(in *input) string {
+	var values map[string]interface{}
+	if _, ok := in.statement.(*sqlparser.Update); ok {
+		values = in.values()
+	} else {
+		rows := in.rows()
+		if vc.row >= len(rows) {
+			return fmt.Sprintf("%d rows", len(rows))
+		}
+		values = rows[vc.row]
+	}
+	v, ok := values[vc.col]
+	if !ok {
+		return fmt.Sprintf("no value for %s", vc.col)
+	}
+	return fmt.Sprintf("%v", v)
+}
+
+func explainStrings(strs []string) string {
+	if len(strs) == 0 {
+		return "(none)"
+	}
+	return strings.Join(strs, ", ")
+}
@@ -0,0 +1,84 @@
+package mogi_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/guregu/mogi"
+)
+
+func TestUnstubbedError(t *testing.T) {
+	defer mogi.Reset()
+	db := openDB()
+
+	mogi.Select().From("beer").Where("id", 10).StubCSV(`10,Apex,Bear Republic Brewing Co.,8.95`)
+	mogi.Select("id", "name").From("beers").Where("id", 11).StubCSV(`11,Apex`)
+	mogi.Select().From("wine").Where("id", 11).Args(12).StubCSV(`11,Riesling`)
+	mogi.Select().From("sake").Where("id", 1).Args(2).Priority(-1).StubCSV(`1,Dassai`)
+	mogi.Insert().Into("beer").StubResult(1, 1)
+
+	_, err := db.Query("SELECT id, name, brewery, pct FROM beer WHERE id = ?", 11)
+	if !errors.Is(err, mogi.ErrUnstubbed) {
+		t.Fatal("err should be ErrUnstubbed but is", err)
+	}
+	var uerr *mogi.UnstubbedError
+	if !errors.As(err, &uerr) {
+		t.Fatalf("err should be *UnstubbedError but is %T", err)
+	}
+	if uerr.Query != "SELECT id, name, brewery, pct FROM beer WHERE id = ?" {
+		t.Error("bad query:", uerr.Query)
+	}
+	expect := []mogi.Mismatch{
+		{
+			Stub:    "SELECT (any) FROM beer WHERE id ≈ [10]",
+			Reasons: []string{"WHERE id ≈ [10]: got 11"},
+		},
+		{
+			Stub: "SELECT id, name FROM beers WHERE id ≈ [11]",
+			Reasons: []string{
+				"SELECT id, name: got id, name, brewery, pct",
+				"FROM beers: got beer",
+			},
+		},
+		{
+			Stub: "SELECT (any) FROM wine WHERE id ≈ [11] WITH ARGS [12]",
+			Reasons: []string{
+				"FROM wine: got beer",
+				"WITH ARGS [12]: got [11]",
+			},
+		},
+	}
+	if !reflect.DeepEqual(uerr.Candidates, expect) {
+		t.Errorf("bad candidates:\n%#v\n≠\n%#v", uerr.Candidates, expect)
+	}
+}
+
+func TestUnstubbedErrorUsedUp(t *testing.T) {
+	defer mogi.Reset()
+	db := openDB()
+
+	mogi.Delete().Table("beer").WhereOp("id", ">", 100).StubRowsAffected(1)
+	mogi.Delete().Table("beer").Where("id", 42).Once().StubRowsAffected(1)
+
+	_, err := db.Exec("DELETE FROM beer WHERE id = ?", 42)
+	checkNil(t, err)
+	_, err = db.Exec("DELETE FROM beer WHERE id = ?", 42)
+	var uerr *mogi.UnstubbedError
+	if !errors.As(err, &uerr) {
+		t.Fatalf("err should be *UnstubbedError but is %T", err)
+	}
+	expect := []mogi.Mismatch{
+		{
+			Stub:    "DELETE TABLE beer WHERE id ≈ [42]",
+			Reasons: []string{"used up: expected exactly 1 time, but matched 1 time too many"},
+		},
+		{
+			Stub:    "DELETE TABLE beer WHERE id > [100]",
+			Reasons: []string{"WHERE id > [100]: got id = 42"},
+		},
+	}
+	if !reflect.DeepEqual(uerr.Candidates, expect) {
+		t.Errorf("bad candidates:\n%#v\n≠\n%#v", uerr.Candidates, expect)
+	}
+}
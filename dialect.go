@@ -0,0 +1,203 @@
+package mogi
+
+import (
+	"bytes"
+	"strings"
+)
+
+// Dialect is a flavor of SQL. Queries are converted from their dialect
+// to the MySQL-flavored SQL that mogi's parser understands.
+type Dialect int
+
+const (
+	// MySQL is the default dialect, using ? placeholders and `backtick` quoted identifiers.
+	MySQL Dialect = iota
+	// PostgreSQL uses $1, $2... placeholders and "double quoted" identifiers.
+	// Type casts such as $1::int are ignored, and backslashes in strings are literal characters.
+	PostgreSQL
+)
+
+func (d Dialect) String() string {
+	switch d {
+	case MySQL:
+		return "MySQL"
+	case PostgreSQL:
+		return "PostgreSQL"
+	}
+	return "Dialect(?)"
+}
+
+// translate converts a query in this dialect to MySQL.
+func (d Dialect) translate(query string) string {
+	switch d {
+	case PostgreSQL:
+		return translatePostgreSQL(query)
+	}
+	return query
+}
+
+// translatePostgreSQL converts $N placeholders to :vN args, which mogi's parser
+// treats the same as the Nth ? placeholder, quoted identifiers to backticks,
+// and $$ quoted strings to regular strings, and strips type casts.
+func translatePostgreSQL(query string) string {
+	var buf bytes.Buffer
+	buf.Grow(len(query))
+	for i := 0; i < len(query); i++ {
+		ch := query[i]
+		switch {
+		case ch == '\'':
+			// string literal, '' is an escaped quote.
+			// Backslashes are literal characters, so they are escaped for the MySQL lexer.
+			end := i + 1
+			for end < len(query) {
+				if query[end] == '\'' {
+					if end+1 < len(query) && query[end+1] == '\'' {
+						end += 2
+						continue
+					}
+					break
+				}
+				end++
+			}
+			if end >= len(query) {
+				end = len(query) - 1
+			}
+			buf.WriteString(strings.Replace(query[i:end+1], `\`, `\\`, -1))
+			i = end
+		case ch == '"':
+			// quoted identifier, "" is an escaped quote
+			var ident bytes.Buffer
+			end := i + 1
+			for ; end < len(query); end++ {
+				if query[end] == '"' {
+					if end+1 < len(query) && query[end+1] == '"' {
+						ident.WriteByte('"')
+						end++
+						continue
+					}
+					break
+				}
+				ident.WriteByte(query[end])
+			}
+			buf.WriteByte('`')
+			buf.WriteString(strings.Replace(ident.String(), "`", "``", -1))
+			buf.WriteByte('`')
+			i = end
+		case ch == '-' && i+1 < len(query) && query[i+1] == '-':
+			// comment until end of line
+			end := strings.IndexByte(query[i:], '\n')
+			if end == -1 {
+				end = len(query) - i
+			}
+			buf.WriteString(query[i : i+end])
+			i += end - 1
+		case ch == '$' && i+1 < len(query) && isDigitByte(query[i+1]):
+			end := i + 1
+			for end < len(query) && isDigitByte(query[end]) {
+				end++
+			}
+			buf.WriteString(":v")
+			buf.WriteString(query[i+1 : end])
+			i = end - 1
+		case ch == '$' && dollarTag(query[i:]) != "":
+			// dollar-quoted string, converted to a regular string literal
+			tag := dollarTag(query[i:])
+			start := i + len(tag)
+			end := strings.Index(query[start:], tag)
+			if end == -1 {
+				end = len(query) - start
+			}
+			body := query[start : start+end]
+			buf.WriteByte('\'')
+			buf.WriteString(strings.NewReplacer(`\`, `\\`, `'`, `''`).Replace(body))
+			buf.WriteByte('\'')
+			i = start + end + len(tag) - 1
+		case ch == ':' && i+1 < len(query) && query[i+1] == ':':
+			// type cast
+			i = castEnd(query, i+2) - 1
+		default:
+			buf.WriteByte(ch)
+		}
+	}
+	return buf.String()
+}
+
+func isDigitByte(ch byte) bool {
+	return '0' <= ch && ch <= '9'
+}
+
+func isIdentByte(ch byte) bool {
+	return 'a' <= ch && ch <= 'z' || 'A' <= ch && ch <= 'Z' || ch == '_' || isDigitByte(ch)
+}
+
+// dollarTag returns the opening tag of the dollar-quoted string at the start of s, such as $$ or $body$,
+// or "" if there isn't one.
+func dollarTag(s string) string {
+	if len(s) < 2 || s[0] != '$' || isDigitByte(s[1]) {
+		return ""
+	}
+	for end := 1; end < len(s); end++ {
+		switch {
+		case s[end] == '$':
+			return s[:end+1]
+		case !isIdentByte(s[end]):
+			return ""
+		}
+	}
+	return ""
+}
+
+// castEnd returns the end of the type name of a cast starting at start, like "int",
+// "varchar(255)", "text[]", "character varying", or "timestamp(3) with time zone".
+func castEnd(query string, start int) int {
+	end := skipSpaces(query, start)
+	wordStart := end
+	for end < len(query) && (isIdentByte(query[end]) || query[end] == '.') {
+		end++
+	}
+	switch strings.ToLower(query[wordStart:end]) {
+	case "character", "char", "bit":
+		end = skipWords(query, end, "varying")
+	case "double":
+		end = skipWords(query, end, "precision")
+	}
+	if next := skipSpaces(query, end); next < len(query) && query[next] == '(' {
+		if close := strings.IndexByte(query[next:], ')'); close != -1 {
+			end = next + close + 1
+		}
+	}
+	end = skipWords(query, end, "with", "time", "zone")
+	end = skipWords(query, end, "without", "time", "zone")
+	for next := skipSpaces(query, end); next+1 < len(query) && query[next] == '['; next = skipSpaces(query, end) {
+		close := strings.IndexByte(query[next:], ']')
+		if close == -1 {
+			break
+		}
+		end = next + close + 1
+	}
+	return end
+}
+
+// skipWords returns the position after the given words, case insensitive and separated by spaces,
+// or pos if they don't come next.
+func skipWords(query string, pos int, words ...string) int {
+	end := pos
+	for _, word := range words {
+		end = skipSpaces(query, end)
+		if end == pos || end+len(word) > len(query) || !strings.EqualFold(query[end:end+len(word)], word) {
+			return pos
+		}
+		end += len(word)
+		if end < len(query) && isIdentByte(query[end]) {
+			return pos
+		}
+	}
+	return end
+}
+
+func skipSpaces(query string, pos int) int {
+	for pos < len(query) && (query[pos] == ' ' || query[pos] == '\t' || query[pos] == '\n' || query[pos] == '\r') {
+		pos++
+	}
+	return pos
+}
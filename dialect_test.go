@@ -0,0 +1,69 @@
+package mogi_test
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/guregu/mogi"
+)
+
+func TestPostgreSQL(t *testing.T) {
+	reg := mogi.New("postgres")
+	reg.SetDialect(mogi.PostgreSQL)
+	defer reg.Reset()
+	db, _ := sql.Open("mogi", "postgres")
+
+	reg.Select("id", "name", "brewery", "pct").From("beer").Where("pct", 5).Args(5).StubCSV(beerCSV)
+	rows, err := db.Query(`SELECT "id", "name", brewery, pct FROM "beer" WHERE pct > $1`, 5)
+	checkNil(t, err)
+	rows.Close()
+
+	// out of order placeholders
+	reg.Select().From("beer").Where("id", 3).Where("brewery", "Mikkeller").StubCSV(`3,Mikkel’s Dream,Mikkeller,4.6`)
+	rows, err = db.Query(`SELECT id, name, brewery, pct FROM beer WHERE brewery = $2 AND id = $1::int`, 3, "Mikkeller")
+	checkNil(t, err)
+	rows.Close()
+
+	// strings and comments are left alone
+	reg.Select().From("beer").Where("name", `it's $1 "beer"`).Where("id", 3).StubCSV(`3,Mikkel’s Dream,Mikkeller,4.6`)
+	rows, err = db.Query(`SELECT id -- $5
+		FROM beer WHERE name = 'it''s $1 "beer"' AND id = $1`, 3)
+	checkNil(t, err)
+	rows.Close()
+
+	// multi-word casts
+	reg.Select().From("beer").Where("brewed_at", "2016-01-02").Where("brewery", "BrewDog").Where("pct", 5.6).StubCSV(`2,Punk IPA,BrewDog,5.6`)
+	rows, err = db.Query(`SELECT id, name, brewery, pct FROM beer
+		WHERE brewed_at = $1::timestamp(3) with time zone AND brewery = $2::character varying(255) AND pct = $3::double precision`,
+		"2016-01-02", "BrewDog", 5.6)
+	checkNil(t, err)
+	rows.Close()
+
+	// backslashes are literal in strings
+	reg.Select().From("beer").Where("name", `C:\`).Where("id", 3).StubCSV(`3,Mikkel’s Dream,Mikkeller,4.6`)
+	rows, err = db.Query(`SELECT id, name, brewery, pct FROM beer WHERE name = 'C:\' AND id = $1`, 3)
+	checkNil(t, err)
+	rows.Close()
+
+	// dollar-quoted strings are left alone
+	reg.Select().From("beer").Where("name", `it's $1\`).Where("id", 3).StubCSV(`3,Mikkel’s Dream,Mikkeller,4.6`)
+	rows, err = db.Query(`SELECT id, name, brewery, pct FROM beer WHERE name = $tag$it's $1\$tag$ AND id = $1`, 3)
+	checkNil(t, err)
+	rows.Close()
+
+	reg.Insert("name", "brewery", "pct").Into("beer").ValueAt(0, "pct", 4.6).ValueAt(1, "name", "Tokyo*").StubResult(4, 2)
+	_, err = db.Exec(`INSERT INTO "beer" ("name", "brewery", "pct") VALUES ($1, 'Mikkeller', 4.6), ($2, $3, $4)`,
+		"Mikkel’s Dream",
+		"Tokyo*", "BrewDog", 18.2,
+	)
+	checkNil(t, err)
+
+	reg.Update("pct").Table("beer").Value("pct", 4.7).Where("id", 3).StubRowsAffected(1)
+	_, err = db.Exec(`UPDATE beer SET "pct" = $2 WHERE "id" = $1`, 3, 4.7)
+	checkNil(t, err)
+
+	// the default dialect is still MySQL
+	if mogi.New("").Dialect() != mogi.MySQL {
+		t.Error("default dialect should be MySQL")
+	}
+}
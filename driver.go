@@ -2,22 +2,36 @@ package mogi
 
 import (
 	"database/sql/driver"
+	"sync"
 )
 
 var drv *mdriver
 
 type mdriver struct {
-	*conn
+	mu         sync.Mutex
+	registries map[string]*Registry
 }
 
 func newDriver() *mdriver {
 	return &mdriver{
-		conn: newConn(),
+		registries: make(map[string]*Registry),
 	}
 }
 
+// registry returns the registry for the given DSN, creating it if necessary.
+func (d *mdriver) registry(dsn string) *Registry {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	r, ok := d.registries[dsn]
+	if !ok {
+		r = newRegistry(dsn)
+		d.registries[dsn] = r
+	}
+	return r
+}
+
 func (d *mdriver) Open(name string) (driver.Conn, error) {
-	return drv.conn, nil
+	return newConn(d.registry(name)), nil
 }
 
 type execResult struct {
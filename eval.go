@@ -0,0 +1,504 @@
+package mogi
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/guregu/mogi/internal/sqlparser"
+)
+
+// evaluator evaluates expressions against a single row.
+// Placeholders are resolved using the query's input.
+type evaluator struct {
+	in   *input
+	cols []string
+	row  []driver.Value
+}
+
+// column returns the value of the given column in the current row.
+func (e evaluator) column(name string) (interface{}, error) {
+	for i, col := range e.cols {
+		if strings.EqualFold(col, name) {
+			if i >= len(e.row) {
+				return nil, fmt.Errorf("mogi: row has %d values, missing column %s", len(e.row), name)
+			}
+			return e.row[i], nil
+		}
+	}
+	return nil, fmt.Errorf("mogi: unknown column: %s", name)
+}
+
+// value evaluates a value expression.
+// NULL is nil, numbers are int64 or float64, and strings are string.
+func (e evaluator) value(expr sqlparser.Expr) (interface{}, error) {
+	switch x := expr.(type) {
+	case *sqlparser.ColName:
+		if x.Qualifier == "" && strings.HasPrefix(string(x.Name), "@") {
+			return e.in.resolve(namedArg(x.Name)), nil
+		}
+		return e.column(string(x.Name))
+	case sqlparser.ValArg:
+		return e.in.resolve(transmogrify(x)), nil
+	case sqlparser.StrVal:
+		return string(x), nil
+	case sqlparser.NumVal:
+		return transmogrify(x), nil
+	case *sqlparser.NullVal:
+		return nil, nil
+	case sqlparser.BoolVal:
+		return bool(x), nil
+	case sqlparser.ValTuple:
+		if len(x) == 1 {
+			// parenthesized expression
+			return e.value(x[0])
+		}
+		vals := make([]interface{}, 0, len(x))
+		for _, item := range x {
+			v, err := e.value(item)
+			if err != nil {
+				return nil, err
+			}
+			vals = append(vals, v)
+		}
+		return vals, nil
+	case *sqlparser.UnaryExpr:
+		v, err := e.value(x.Expr)
+		if err != nil || v == nil {
+			return nil, err
+		}
+		switch x.Operator {
+		case sqlparser.UPlusStr:
+			return v, nil
+		case sqlparser.UMinusStr:
+			return arithmetic(sqlparser.MinusStr, int64(0), v)
+		}
+	case *sqlparser.BinaryExpr:
+		left, err := e.value(x.Left)
+		if err != nil {
+			return nil, err
+		}
+		right, err := e.value(x.Right)
+		if err != nil {
+			return nil, err
+		}
+		return arithmetic(x.Operator, left, right)
+	}
+	return nil, fmt.Errorf("mogi: unsupported expression: %s", sqlparser.String(expr))
+}
+
+// truth evaluates a boolean expression using SQL's three-valued logic.
+// The result is invalid (NULL) if the expression is unknown.
+func (e evaluator) truth(expr sqlparser.BoolExpr) (sql.NullBool, error) {
+	switch x := expr.(type) {
+	case *sqlparser.AndExpr:
+		left, err := e.truth(x.Left)
+		if err != nil {
+			return left, err
+		}
+		if left.Valid && !left.Bool {
+			return left, nil
+		}
+		right, err := e.truth(x.Right)
+		if err != nil {
+			return right, err
+		}
+		if right.Valid && !right.Bool {
+			return right, nil
+		}
+		return sql.NullBool{Bool: true, Valid: left.Valid && right.Valid}, nil
+	case *sqlparser.OrExpr:
+		left, err := e.truth(x.Left)
+		if err != nil {
+			return left, err
+		}
+		if left.Valid && left.Bool {
+			return left, nil
+		}
+		right, err := e.truth(x.Right)
+		if err != nil {
+			return right, err
+		}
+		if right.Valid && right.Bool {
+			return right, nil
+		}
+		return sql.NullBool{Valid: left.Valid && right.Valid}, nil
+	case *sqlparser.NotExpr:
+		v, err := e.truth(x.Expr)
+		v.Bool = !v.Bool
+		return v, err
+	case *sqlparser.ParenBoolExpr:
+		return e.truth(x.Expr)
+	case sqlparser.BoolVal:
+		return sql.NullBool{Bool: bool(x), Valid: true}, nil
+	case *sqlparser.ComparisonExpr:
+		return e.comparison(x)
+	case *sqlparser.RangeCond:
+		return e.between(x)
+	case *sqlparser.IsExpr:
+		return e.is(x)
+	}
+	return sql.NullBool{}, fmt.Errorf("mogi: unsupported expression: %s", sqlparser.String(expr))
+}
+
+// satisfies returns true if the current row satisfies the WHERE clause.
+func (e evaluator) satisfies(where *sqlparser.Where) (bool, error) {
+	if where == nil {
+		return true, nil
+	}
+	v, err := e.truth(where.Expr)
+	return v.Valid && v.Bool, err
+}
+
+func (e evaluator) comparison(expr *sqlparser.ComparisonExpr) (sql.NullBool, error) {
+	left, err := e.value(expr.Left)
+	if err != nil {
+		return sql.NullBool{}, err
+	}
+	right, err := e.value(expr.Right)
+	if err != nil {
+		return sql.NullBool{}, err
+	}
+
+	switch expr.Operator {
+	case sqlparser.InStr, sqlparser.NotInStr:
+		list, ok := right.([]interface{})
+		if !ok {
+			list = []interface{}{right}
+		}
+		v := inList(left, list)
+		if expr.Operator == sqlparser.NotInStr {
+			v.Bool = !v.Bool
+		}
+		return v, nil
+	case sqlparser.NullSafeEqualStr:
+		if left == nil || right == nil {
+			return sql.NullBool{Bool: left == nil && right == nil, Valid: true}, nil
+		}
+		cmp, ok := compare(left, right)
+		return sql.NullBool{Bool: ok && cmp == 0, Valid: true}, nil
+	}
+
+	if left == nil || right == nil {
+		return sql.NullBool{}, nil
+	}
+
+	switch expr.Operator {
+	case sqlparser.LikeStr, sqlparser.NotLikeStr:
+		re, err := likeToRegexp(stringify(normalize(right)))
+		if err != nil {
+			return sql.NullBool{}, err
+		}
+		v := re.MatchString(stringify(normalize(left)))
+		return sql.NullBool{Bool: v == (expr.Operator == sqlparser.LikeStr), Valid: true}, nil
+	case sqlparser.RegexpStr, sqlparser.NotRegexpStr:
+		re, err := regexp.Compile("(?i)" + stringify(normalize(right)))
+		if err != nil {
+			return sql.NullBool{}, err
+		}
+		v := re.MatchString(stringify(normalize(left)))
+		return sql.NullBool{Bool: v == (expr.Operator == sqlparser.RegexpStr), Valid: true}, nil
+	}
+
+	cmp, ok := compare(left, right)
+	if !ok {
+		if expr.Operator == sqlparser.NotEqualStr {
+			return sql.NullBool{Bool: true, Valid: true}, nil
+		}
+		return sql.NullBool{Valid: true}, nil
+	}
+	var v bool
+	switch expr.Operator {
+	case sqlparser.EqualStr:
+		v = cmp == 0
+	case sqlparser.NotEqualStr:
+		v = cmp != 0
+	case sqlparser.LessThanStr:
+		v = cmp < 0
+	case sqlparser.LessEqualStr:
+		v = cmp <= 0
+	case sqlparser.GreaterThanStr:
+		v = cmp > 0
+	case sqlparser.GreaterEqualStr:
+		v = cmp >= 0
+	default:
+		return sql.NullBool{}, fmt.Errorf("mogi: unsupported operator: %s", expr.Operator)
+	}
+	return sql.NullBool{Bool: v, Valid: true}, nil
+}
+
+// between evaluates BETWEEN and NOT BETWEEN.
+func (e evaluator) between(expr *sqlparser.RangeCond) (sql.NullBool, error) {
+	v, err := e.value(expr.Left)
+	if err != nil {
+		return sql.NullBool{}, err
+	}
+	from, err := e.value(expr.From)
+	if err != nil {
+		return sql.NullBool{}, err
+	}
+	to, err := e.value(expr.To)
+	if err != nil {
+		return sql.NullBool{}, err
+	}
+	if v == nil || from == nil || to == nil {
+		return sql.NullBool{}, nil
+	}
+	lo, ok1 := compare(v, from)
+	hi, ok2 := compare(v, to)
+	if !ok1 || !ok2 {
+		return sql.NullBool{}, nil
+	}
+	between := lo >= 0 && hi <= 0
+	return sql.NullBool{Bool: between == (expr.Operator == sqlparser.BetweenStr), Valid: true}, nil
+}
+
+// is evaluates IS [NOT] NULL, IS [NOT] TRUE, and IS [NOT] FALSE.
+// These are never NULL.
+func (e evaluator) is(expr *sqlparser.IsExpr) (sql.NullBool, error) {
+	var v sql.NullBool
+	if bexpr, ok := expr.Expr.(sqlparser.BoolExpr); ok {
+		var err error
+		if v, err = e.truth(bexpr); err != nil {
+			return v, err
+		}
+	} else {
+		val, err := e.value(expr.Expr)
+		if err != nil {
+			return v, err
+		}
+		if val != nil {
+			f, _ := toFloat(normalize(val))
+			v = sql.NullBool{Bool: f != 0, Valid: true}
+		}
+	}
+
+	var is bool
+	switch expr.Operator {
+	case sqlparser.IsNullStr:
+		is = !v.Valid
+	case sqlparser.IsNotNullStr:
+		is = v.Valid
+	case sqlparser.IsTrueStr:
+		is = v.Valid && v.Bool
+	case sqlparser.IsNotTrueStr:
+		is = !(v.Valid && v.Bool)
+	case sqlparser.IsFalseStr:
+		is = v.Valid && !v.Bool
+	case sqlparser.IsNotFalseStr:
+		is = !(v.Valid && !v.Bool)
+	default:
+		return sql.NullBool{}, fmt.Errorf("mogi: unsupported operator: %s", expr.Operator)
+	}
+	return sql.NullBool{Bool: is, Valid: true}, nil
+}
+
+// likeToRegexp converts a LIKE pattern to a case-insensitive regexp.
+func likeToRegexp(pattern string) (*regexp.Regexp, error) {
+	var re strings.Builder
+	re.WriteString("(?is)^")
+	escaped := false
+	for _, r := range pattern {
+		switch {
+		case escaped:
+			re.WriteString(regexp.QuoteMeta(string(r)))
+			escaped = false
+		case r == '\\':
+			escaped = true
+		case r == '%':
+			re.WriteString(".*")
+		case r == '_':
+			re.WriteString(".")
+		default:
+			re.WriteString(regexp.QuoteMeta(string(r)))
+		}
+	}
+	re.WriteString("$")
+	return regexp.Compile(re.String())
+}
+
+// inList returns true if v is equal to an item in list.
+func inList(v interface{}, list []interface{}) sql.NullBool {
+	if v == nil {
+		return sql.NullBool{}
+	}
+	sawNull := false
+	for _, item := range list {
+		if item == nil {
+			sawNull = true
+			continue
+		}
+		if cmp, ok := compare(v, item); ok && cmp == 0 {
+			return sql.NullBool{Bool: true, Valid: true}
+		}
+	}
+	return sql.NullBool{Valid: !sawNull}
+}
+
+// compare compares two non-NULL values, returning -1, 0, or +1.
+// Numbers and numeric strings compare as numbers, and times compare with
+// strings formatted according to ParseTime (or MySQL's DATETIME format).
+// ok is false if the values can't be compared.
+func compare(a, b interface{}) (cmp int, ok bool) {
+	a, b = normalize(a), normalize(b)
+
+	// times
+	if at, ok := a.(time.Time); ok {
+		bt, ok := toTime(b)
+		if !ok {
+			return 0, false
+		}
+		return compareTimes(at, bt), true
+	}
+	if bt, ok := b.(time.Time); ok {
+		at, ok := toTime(a)
+		if !ok {
+			return 0, false
+		}
+		return compareTimes(at, bt), true
+	}
+
+	// numbers
+	if ai, ok := a.(int64); ok {
+		if bi, ok := b.(int64); ok {
+			return compareInts(ai, bi), true
+		}
+	}
+	af, aok := toFloat(a)
+	bf, bok := toFloat(b)
+	if aok && bok {
+		switch {
+		case af < bf:
+			return -1, true
+		case af > bf:
+			return 1, true
+		}
+		return 0, true
+	}
+
+	// strings
+	as, aok := a.(string)
+	bs, bok := b.(string)
+	if aok && bok {
+		return strings.Compare(as, bs), true
+	}
+	if aok || bok {
+		return strings.Compare(stringify(a), stringify(b)), true
+	}
+
+	if equals(a, b) {
+		return 0, true
+	}
+	return 0, false
+}
+
+// normalize unifies v and turns booleans into 0 or 1.
+func normalize(v interface{}) interface{} {
+	v = unify(v)
+	if b, ok := v.(bool); ok {
+		if b {
+			return int64(1)
+		}
+		return int64(0)
+	}
+	return v
+}
+
+func compareInts(a, b int64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+func compareTimes(a, b time.Time) int {
+	switch {
+	case a.Before(b):
+		return -1
+	case a.After(b):
+		return 1
+	}
+	return 0
+}
+
+func toFloat(v interface{}) (float64, bool) {
+	switch x := v.(type) {
+	case int64:
+		return float64(x), true
+	case float64:
+		return x, true
+	case string:
+		f, err := strconv.ParseFloat(strings.TrimSpace(x), 64)
+		return f, err == nil
+	}
+	return 0, false
+}
+
+func toTime(v interface{}) (time.Time, bool) {
+	switch x := v.(type) {
+	case time.Time:
+		return x, true
+	case string:
+		layout := parseTimeLayout()
+		if layout == "" {
+			layout = "2006-01-02 15:04:05"
+		}
+		t, err := time.Parse(layout, x)
+		return t, err == nil
+	}
+	return time.Time{}, false
+}
+
+// arithmetic applies a binary operator to two values.
+// If either value is NULL, the result is NULL.
+func arithmetic(op string, a, b interface{}) (interface{}, error) {
+	if a == nil || b == nil {
+		return nil, nil
+	}
+	a, b = normalize(a), normalize(b)
+
+	ai, aInt := a.(int64)
+	bi, bInt := b.(int64)
+	if aInt && bInt {
+		switch op {
+		case sqlparser.PlusStr:
+			return ai + bi, nil
+		case sqlparser.MinusStr:
+			return ai - bi, nil
+		case sqlparser.MultStr:
+			return ai * bi, nil
+		case sqlparser.ModStr:
+			if bi == 0 {
+				return nil, nil
+			}
+			return ai % bi, nil
+		}
+	}
+
+	af, aok := toFloat(a)
+	bf, bok := toFloat(b)
+	if !aok || !bok {
+		return nil, fmt.Errorf("mogi: can't apply %s to %v and %v", op, a, b)
+	}
+	switch op {
+	case sqlparser.PlusStr:
+		return af + bf, nil
+	case sqlparser.MinusStr:
+		return af - bf, nil
+	case sqlparser.MultStr:
+		return af * bf, nil
+	case sqlparser.DivStr:
+		if bf == 0 {
+			return nil, nil
+		}
+		return af / bf, nil
+	}
+	return nil, fmt.Errorf("mogi: unsupported operator: %s", op)
+}
@@ -1,42 +1,60 @@
 package mogi
 
 import (
+	"database/sql"
 	"database/sql/driver"
+	"time"
 )
 
 // ExecStub is a SQL exec stub (for INSERT, UPDATE, DELETE)
 type ExecStub struct {
+	reg    *Registry
 	chain  condchain
 	result driver.Result
 	err    error
+
+	resultFunc func(q Query) (driver.Result, error)
+
+	// for RETURNING
+	data    [][]driver.Value
+	header  []string              // for StubCSVWithHeader and StubStructs
+	hints   map[string]CSVType    // by lowercase column name
+	types   map[string]columnType // by lowercase column name
+	typeErr error                 // from ColumnType
+	resolve func(in *input) ([][]driver.Value, error)
+
+	count expectation
+	delay latency
+	fail  rowErrors
+
+	seq    *Sequence
+	seqPos int
 }
 
 // Insert starts a new stub for INSERT statements.
 // You can filter out which columns to use this stub for.
 // If you don't pass any columns, it will stub all INSERT queries.
 func Insert(cols ...string) *ExecStub {
-	return &ExecStub{
-		chain: condchain{insertCond{
-			cols: cols,
-		}},
-	}
+	return drv.registry("").Insert(cols...)
 }
 
 // Update starts a new stub for UPDATE statements.
 // You can filter out which columns (from the SET statement) this stub is for.
 // If you don't pass any columns, it will stub all UPDATE queries.
 func Update(cols ...string) *ExecStub {
-	return &ExecStub{
-		chain: condchain{updateCond{
-			cols: cols,
-		}},
-	}
+	return drv.registry("").Update(cols...)
 }
 
 // Delete starts a new stub for DELETE statements.
 func Delete() *ExecStub {
+	return drv.registry("").Delete()
+}
+
+func newExecStub(reg *Registry, c cond) *ExecStub {
 	return &ExecStub{
-		chain: condchain{deleteCond{}},
+		reg:   reg,
+		chain: condchain{c},
+		count: newExpectation(),
 	}
 }
 
@@ -74,20 +92,125 @@ func (s *ExecStub) ValueAt(row int, col string, v interface{}) *ExecStub {
 
 // Where further filters this stub by values of input in the WHERE clause.
 // You can pass multiple values for IN clause matching.
+// Values are found anywhere in the WHERE clause, so OR is treated like AND,
+// and if a column appears more than once only its last value is used.
+// Use WhereExpr to match the structure of the WHERE clause.
 func (s *ExecStub) Where(col string, v ...interface{}) *ExecStub {
 	s.chain = append(s.chain, newWhereCond(col, v))
 	return s
 }
 
 // WhereOp further filters this stub by values of input and the operator used in the WHERE clause.
+// Operators include "=", "<", "like", "not in", "between" (with two values), and "is null" (with no values).
+// Like Where, OR is treated like AND, and only the last use of a column with the same operator is used.
 func (s *ExecStub) WhereOp(col string, operator string, v ...interface{}) *ExecStub {
 	s.chain = append(s.chain, newWhereOpCond(col, v, operator))
 	return s
 }
 
+// WhereBetween further filters this stub by a BETWEEN in the WHERE clause, such as: WHERE col BETWEEN lo AND hi.
+func (s *ExecStub) WhereBetween(col string, lo, hi interface{}) *ExecStub {
+	s.chain = append(s.chain, newWhereOpCond(col, []interface{}{lo, hi}, "between"))
+	return s
+}
+
+// WhereNull further filters this stub by an IS NULL in the WHERE clause, such as: WHERE deleted_at IS NULL.
+func (s *ExecStub) WhereNull(col string) *ExecStub {
+	s.chain = append(s.chain, newWhereOpCond(col, nil, "is null"))
+	return s
+}
+
+// WhereNotNull further filters this stub by an IS NOT NULL in the WHERE clause.
+func (s *ExecStub) WhereNotNull(col string) *ExecStub {
+	s.chain = append(s.chain, newWhereOpCond(col, nil, "is not null"))
+	return s
+}
+
+// WhereLike further filters this stub by a LIKE in the WHERE clause, comparing the pattern as a string.
+func (s *ExecStub) WhereLike(col string, pattern string) *ExecStub {
+	s.chain = append(s.chain, newWhereOpCond(col, []interface{}{pattern}, "like"))
+	return s
+}
+
+// WhereNotIn further filters this stub by a NOT IN in the WHERE clause.
+func (s *ExecStub) WhereNotIn(col string, v ...interface{}) *ExecStub {
+	s.chain = append(s.chain, newWhereOpCond(col, v, "not in"))
+	return s
+}
+
+// WhereExpr further filters this stub by the logical structure of the WHERE clause, such as "a = 1 OR b = ?".
+// Placeholders in expr are replaced by args, and placeholders in the query by the query's args.
+// Parentheses and the order of the operands of AND and OR don't matter.
+// If expr is malformed, queries that would otherwise match this stub return an error describing it.
+func (s *ExecStub) WhereExpr(expr string, args ...interface{}) *ExecStub {
+	s.chain = append(s.chain, newWhereExprCond(s.reg.Dialect(), expr, args))
+	return s
+}
+
+// WhereRow further filters this stub, matching queries whose WHERE clause would be satisfied by the given row.
+// The row's keys are column names. Queries using a column missing from the row don't match.
+func (s *ExecStub) WhereRow(row map[string]interface{}) *ExecStub {
+	s.chain = append(s.chain, newWhereRowCond(row))
+	return s
+}
+
 // Args further filters this stub, matching based on the args passed to the query
 func (s *ExecStub) Args(args ...driver.Value) *ExecStub {
-	s.chain = append(s.chain, argsCond{args})
+	s.chain = append(s.chain, newArgsCond(args))
+	return s
+}
+
+// ArgNamed further filters this stub, matching based on the named arg (sql.Named) passed to the query
+func (s *ExecStub) ArgNamed(name string, v interface{}) *ExecStub {
+	s.chain = append(s.chain, newArgNamedCond(name, v))
+	return s
+}
+
+// InTx further filters this stub, matching queries run inside of a transaction.
+func (s *ExecStub) InTx() *ExecStub {
+	s.chain = append(s.chain, txCond{})
+	return s
+}
+
+// Isolation further filters this stub, matching queries run inside of a transaction
+// with the given isolation level (see sql.TxOptions).
+func (s *ExecStub) Isolation(level sql.IsolationLevel) *ExecStub {
+	s.chain = append(s.chain, isolationCond{level})
+	return s
+}
+
+// ReadOnly further filters this stub, matching queries run inside of a transaction
+// whose read-only option (see sql.TxOptions) is readOnly.
+func (s *ExecStub) ReadOnly(readOnly bool) *ExecStub {
+	s.chain = append(s.chain, readOnlyCond{readOnly})
+	return s
+}
+
+// Match further filters this stub with a custom matcher, adding the given priority.
+// fn is called without holding any of mogi's locks, so it may call other functions of this package,
+// but it may be called concurrently for queries on different connections.
+func (s *ExecStub) Match(fn func(q Query) bool, priority int) *ExecStub {
+	s.chain = append(s.chain, matchCond{fn: fn, p: priority})
+	return s
+}
+
+// TypeHint sets the type of a column for CSV data, so its cells are converted from text to that type.
+// col is the name of the column in the RETURNING clause.
+func (s *ExecStub) TypeHint(col string, typ CSVType) *ExecStub {
+	s.hints = newTypeHints(s.hints, col, typ)
+	return s
+}
+
+// ColumnType declares the type of a result column, as reported by sql.Rows.ColumnTypes.
+// decl is a MySQL column type such as "BIGINT UNSIGNED", "VARCHAR(255) NOT NULL", or "DECIMAL(10,2)".
+// Columns without a declared type have their type inferred from the stubbed values.
+// If decl is not a known type, matching queries return an error.
+func (s *ExecStub) ColumnType(col string, decl string) *ExecStub {
+	types, err := newColumnTypes(s.types, col, decl)
+	if err != nil && s.typeErr == nil {
+		s.typeErr = err
+	}
+	s.types = types
 	return s
 }
 
@@ -110,10 +233,79 @@ func (s *ExecStub) Dump() *ExecStub {
 	return s
 }
 
+// Times limits this stub to matching n times.
+// Once used up, queries fall through to the next stub.
+// ExpectationsWereMet will report this stub unless it matched exactly n times.
+func (s *ExecStub) Times(n int) *ExecStub {
+	s.count.times(n)
+	return s
+}
+
+// Once is a shortcut for Times(1).
+func (s *ExecStub) Once() *ExecStub {
+	return s.Times(1)
+}
+
+// AtMost limits this stub to matching at most n times.
+// Once used up, queries fall through to the next stub.
+func (s *ExecStub) AtMost(n int) *ExecStub {
+	s.count.atMost(n)
+	return s
+}
+
+// AtLeast has ExpectationsWereMet report this stub unless it matched at least n times.
+// It doesn't limit matching.
+func (s *ExecStub) AtLeast(n int) *ExecStub {
+	s.count.atLeast(n)
+	return s
+}
+
+// Never is a shortcut for Times(0).
+// This stub will never match, but ExpectationsWereMet will report it if it would have.
+func (s *ExecStub) Never() *ExecStub {
+	return s.Times(0)
+}
+
+// Delay makes queries matching this stub wait for d before returning.
+// If the query's context is cancelled first, the query returns the context's error.
+func (s *ExecStub) Delay(d time.Duration) *ExecStub {
+	s.delay = latency{delay: d}
+	return s
+}
+
+// DelayJitter is like Delay, but adds a random duration between 0 and jitter to each wait.
+func (s *ExecStub) DelayJitter(d, jitter time.Duration) *ExecStub {
+	s.delay = latency{delay: d, jitter: jitter}
+	return s
+}
+
+// FailAfterRows makes reading the rows fail with err after n rows have been read,
+// like a connection dropping halfway through a result.
+// sql.Rows.Next will return false and sql.Rows.Err will return err.
+func (s *ExecStub) FailAfterRows(n int, err error) *ExecStub {
+	s.fail.after, s.fail.err = n, err
+	return s
+}
+
+// FailOnClose makes closing the rows return err.
+// sql.Rows.Close only returns it if the rows are closed before they are all read.
+func (s *ExecStub) FailOnClose(err error) *ExecStub {
+	s.fail.closeErr = err
+	return s
+}
+
+// InSequence adds this stub to the end of the given sequence.
+// It will only match after the stubs added before it have matched, and not after the stubs added after it.
+func (s *ExecStub) InSequence(seq *Sequence) *ExecStub {
+	s.seq = seq
+	s.seqPos = seq.add(&s.chain, &s.count)
+	return s
+}
+
 // Stub takes a driver.Result and registers this stub with the driver
 func (s *ExecStub) Stub(res driver.Result) {
 	s.result = res
-	addExecStub(s)
+	s.reg.addExecStub(s)
 }
 
 // StubResult is an easy way to stub a driver.Result.
@@ -123,7 +315,7 @@ func (s *ExecStub) StubResult(lastInsertID, rowsAffected int64) {
 		lastInsertID: lastInsertID,
 		rowsAffected: rowsAffected,
 	}
-	addExecStub(s)
+	s.reg.addExecStub(s)
 }
 
 // StubRowsAffected is an easy way to stub a driver.Result when you only need to specify the rows affected.
@@ -131,18 +323,117 @@ func (s *ExecStub) StubRowsAffected(rowsAffected int64) {
 	s.StubResult(-1, rowsAffected)
 }
 
+// StubCSV takes CSV data and registers this stub with the driver.
+// The data is returned as rows when the statement is run with Query, such as for INSERT ... RETURNING.
+// When run with Exec, the result's rows affected is the number of rows.
+func (s *ExecStub) StubCSV(data string) {
+	s.resolve = func(in *input) ([][]driver.Value, error) {
+		return csvToValues(in.returning(), data, s.hints)
+	}
+	s.reg.addExecStub(s)
+}
+
+// StubCSVWithHeader takes CSV data whose first row names the columns, and registers this stub with the driver.
+// The columns are picked out and reordered to match the RETURNING clause, and RETURNING * returns all of them.
+// When run with Exec, the result's rows affected is the number of rows, not including the header.
+func (s *ExecStub) StubCSVWithHeader(data string) {
+	s.header = csvHeader(data)
+	s.resolve = func(in *input) ([][]driver.Value, error) {
+		return projectCSV(in.returning(), data, s.hints)
+	}
+	s.reg.addExecStub(s)
+}
+
+// StubRows takes row data and registers this stub with the driver.
+// The rows are returned when the statement is run with Query, such as for INSERT ... RETURNING.
+// When run with Exec, the result's rows affected is the number of rows.
+func (s *ExecStub) StubRows(rows [][]driver.Value) {
+	s.data = rows
+	s.reg.addExecStub(s)
+}
+
+// StubStructs takes a slice of structs (or maps) and registers this stub with the driver.
+// Each column of the RETURNING clause is taken from the field with the same name in its db tag (or field name).
+// RETURNING * returns every field of the struct in order, like Stub.StubStructs.
+// When run with Exec, the result's rows affected is the number of items.
+// If slice isn't a slice or array, statements return an error.
+func (s *ExecStub) StubStructs(slice interface{}) {
+	rv, err := checkStructs(slice)
+	if err == nil {
+		s.header = structColumns(rv)
+	}
+	s.resolve = func(in *input) ([][]driver.Value, error) {
+		if err != nil {
+			return nil, err
+		}
+		return structRows(expandStar(in.returning(), s.header), rv)
+	}
+	s.reg.addExecStub(s)
+}
+
+// StubFunc registers this stub to return the result (or error) given by fn,
+// which is called with each matching statement.
+// Use this to return results that depend on the statement, like a LastInsertId taken from the inserted row.
+func (s *ExecStub) StubFunc(fn func(q Query) (driver.Result, error)) {
+	s.resultFunc = fn
+	s.reg.addExecStub(s)
+}
+
 // StubError takes an error and registers this stub with the driver
 func (s *ExecStub) StubError(err error) {
 	s.err = err
-	addExecStub(s)
+	s.reg.addExecStub(s)
 }
 
-func (s *ExecStub) matches(in input) bool {
+func (s *ExecStub) matches(in *input) bool {
 	return s.chain.matches(in)
 }
 
-func (s *ExecStub) results() (driver.Result, error) {
-	return s.result, s.err
+func (s *ExecStub) results(in *input) (driver.Result, error) {
+	switch {
+	case s.err != nil:
+		return nil, s.err
+	case s.typeErr != nil:
+		return nil, s.typeErr
+	case s.resultFunc != nil:
+		return s.resultFunc(Query{in: in})
+	case s.result == nil && (s.data != nil || s.resolve != nil):
+		data, err := s.returningData(in)
+		if err != nil {
+			return nil, err
+		}
+		return execResult{
+			lastInsertID: -1,
+			rowsAffected: int64(len(data)),
+		}, nil
+	}
+	return s.result, nil
+}
+
+// rows returns the RETURNING data of this stub.
+// Stubs without data return no rows.
+func (s *ExecStub) rows(in *input) (*rows, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	if s.typeErr != nil {
+		return nil, s.typeErr
+	}
+	data, err := s.returningData(in)
+	if err != nil {
+		return nil, err
+	}
+	r := newRows(expandStar(in.returning(), s.header), data)
+	r.types = s.types
+	r.fail = s.fail
+	return r, nil
+}
+
+func (s *ExecStub) returningData(in *input) ([][]driver.Value, error) {
+	if s.data == nil && s.resolve != nil {
+		return s.resolve(in)
+	}
+	return s.data, nil
 }
 
 func (s *ExecStub) priority() int {
@@ -0,0 +1,92 @@
+package mogi
+
+import (
+	"fmt"
+)
+
+// expectation limits and tracks how many times a stub is matched.
+// It is guarded by its registry's lock.
+type expectation struct {
+	min int
+	max int // -1 means unlimited
+
+	calls  int // successful matches
+	excess int // would-be matches after an exact expectation was used up
+}
+
+func newExpectation() expectation {
+	return expectation{max: -1}
+}
+
+func (e *expectation) times(n int) {
+	e.min = n
+	e.max = n
+}
+
+func (e *expectation) atLeast(n int) {
+	e.min = n
+}
+
+func (e *expectation) atMost(n int) {
+	e.max = n
+}
+
+// usedUp returns true if this stub can't match any more.
+func (e expectation) usedUp() bool {
+	return e.max >= 0 && e.calls >= e.max
+}
+
+// exact returns true for expectations of an exact number of calls (Times, Once, and Never),
+// which should also report queries that would have matched after being used up.
+func (e expectation) exact() bool {
+	return e.max >= 0 && e.min == e.max
+}
+
+// take records a match.
+func (e *expectation) take() {
+	e.calls++
+}
+
+func (e expectation) limited() bool {
+	return e.min > 0 || e.max >= 0
+}
+
+// unused returns true if this stub was expected to match but never did.
+func (e expectation) unused() bool {
+	if e.limited() && e.min == 0 {
+		return false
+	}
+	return e.calls == 0
+}
+
+func (e expectation) met() bool {
+	return e.calls >= e.min && e.excess == 0
+}
+
+func (e expectation) String() string {
+	switch {
+	case e.max == 0:
+		return "never"
+	case e.min == e.max:
+		return fmt.Sprintf("exactly %s", plural(e.min, "time"))
+	case e.max < 0:
+		return fmt.Sprintf("at least %s", plural(e.min, "time"))
+	case e.min == 0:
+		return fmt.Sprintf("at most %s", plural(e.max, "time"))
+	}
+	return fmt.Sprintf("between %d and %d times", e.min, e.max)
+}
+
+func (e expectation) problem() string {
+	if e.excess > 0 {
+		return fmt.Sprintf("expected %s, but matched %s too many", e, plural(e.excess, "time"))
+	}
+	return fmt.Sprintf("expected %s, but matched %s", e, plural(e.calls, "time"))
+}
+
+func plural(n int, unit string) string {
+	if n == 1 {
+		return fmt.Sprintf("%d %s", n, unit)
+	}
+	return fmt.Sprintf("%d %ss", n, unit)
+}
@@ -0,0 +1,168 @@
+package mogi_test
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/guregu/mogi"
+)
+
+func TestTimes(t *testing.T) {
+	defer mogi.Reset()
+	db := openDB()
+
+	mogi.Update().Table("beer").Once().StubRowsAffected(1)
+	mogi.Update().Table("beer").Priority(-1).StubRowsAffected(0)
+
+	res, err := db.Exec("UPDATE beer SET pct = ? WHERE id = ?", 4.7, 3)
+	checkNil(t, err)
+	checkRowsAffected(t, res, 1)
+	checkNil(t, mogi.ExpectationsWereMet())
+
+	// falls through to the next stub
+	res, err = db.Exec("UPDATE beer SET pct = ? WHERE id = ?", 4.7, 3)
+	checkNil(t, err)
+	checkRowsAffected(t, res, 0)
+	err = mogi.ExpectationsWereMet()
+	if err == nil || !strings.Contains(err.Error(), "too many") {
+		t.Error("expected too many matches error, got", err)
+	}
+
+	mogi.Reset()
+	mogi.Select().From("beer").Times(2).StubCSV(beerCSV)
+	runBeerSelectQuery(t, db)
+	if err := mogi.ExpectationsWereMet(); err == nil {
+		t.Error("expected unmet expectations error after 1 of 2 queries")
+	}
+	runBeerSelectQuery(t, db)
+	checkNil(t, mogi.ExpectationsWereMet())
+	runUnstubbedSelect(t, db)
+}
+
+func TestAtMostAtLeast(t *testing.T) {
+	defer mogi.Reset()
+	db := openDB()
+
+	mogi.Select().AtMost(1).StubCSV(beerCSV)
+	checkNil(t, mogi.ExpectationsWereMet())
+	runBeerSelectQuery(t, db)
+	checkNil(t, mogi.ExpectationsWereMet())
+	runUnstubbedSelect(t, db)
+	// falling through isn't a problem for AtMost
+	checkNil(t, mogi.ExpectationsWereMet())
+
+	mogi.Reset()
+	mogi.Insert().AtLeast(2).StubResult(1, 1)
+	for i := 0; i < 3; i++ {
+		if i == 1 {
+			if err := mogi.ExpectationsWereMet(); err == nil {
+				t.Error("expected unmet expectations error after 1 insert")
+			}
+		}
+		_, err := db.Exec("INSERT INTO beer (name, brewery, pct) VALUES (?, ?, ?)", "Mikkel’s Dream", "Mikkeller", 4.6)
+		checkNil(t, err)
+	}
+	checkNil(t, mogi.ExpectationsWereMet())
+}
+
+func TestUsedUpNotify(t *testing.T) {
+	defer mogi.Reset()
+	db := openDB()
+
+	ch := make(chan struct{}, 2)
+	mogi.Select().Once().Notify(ch).StubCSV(beerCSV)
+	runBeerSelectQuery(t, db)
+	runUnstubbedSelect(t, db)
+	time.Sleep(10 * time.Millisecond)
+	if n := len(ch); n != 1 {
+		t.Error("used up stub should notify once but notified", n, "times")
+	}
+}
+
+func TestNever(t *testing.T) {
+	defer mogi.Reset()
+	db := openDB()
+
+	mogi.Delete().Table("beer").Never().StubRowsAffected(1)
+	checkNil(t, mogi.ExpectationsWereMet())
+	_, err := db.Exec("DELETE FROM beer WHERE id = ?", 42)
+	if !errors.Is(err, mogi.ErrUnstubbed) {
+		t.Error("err should be ErrUnstubbed but is", err)
+	}
+	err = mogi.ExpectationsWereMet()
+	if err == nil || !strings.Contains(err.Error(), "DELETE TABLE beer: expected never") {
+		t.Error("expected never error, got", err)
+	}
+}
+
+// fakeTB records errors instead of failing the test
+type fakeTB struct {
+	testing.TB
+	errors   []string
+	cleanups []func()
+}
+
+func (tb *fakeTB) Helper() {}
+
+func (tb *fakeTB) Errorf(format string, args ...interface{}) {
+	tb.errors = append(tb.errors, fmt.Sprintf(format, args...))
+}
+
+func (tb *fakeTB) Cleanup(f func()) {
+	tb.cleanups = append(tb.cleanups, f)
+}
+
+func TestVerify(t *testing.T) {
+	defer mogi.Reset()
+	db := openDB()
+
+	mogi.Select().From("beer").StubCSV(beerCSV)
+	mogi.Select().From("wine").StubCSV(beerCSV)
+	mogi.Insert().Into("beer").Times(2).StubResult(1, 1)
+	mogi.Delete().Never().StubRowsAffected(1)
+
+	runBeerSelectQuery(t, db)
+	_, err := db.Exec("INSERT INTO beer (name, brewery, pct) VALUES (?, ?, ?)", "Mikkel’s Dream", "Mikkeller", 4.6)
+	checkNil(t, err)
+	_, err = db.Exec("UPDATE beer SET pct = ? WHERE id = ?", 4.7, 3)
+	if !errors.Is(err, mogi.ErrUnstubbed) {
+		t.Error("err should be ErrUnstubbed but is", err)
+	}
+
+	tb := &fakeTB{TB: t}
+	mogi.Verify(tb)
+	expect := []string{
+		"mogi: unused stub: SELECT (any) FROM wine",
+		"mogi: INSERT (any) TABLE beer: expected exactly 2 times, but matched 1 time",
+		"mogi: query not stubbed: UPDATE beer SET pct = ? WHERE id = ? [4.7 3]",
+	}
+	if !reflect.DeepEqual(tb.errors, expect) {
+		t.Errorf("bad errors: %q ≠ %q", tb.errors, expect)
+	}
+}
+
+func TestCleanup(t *testing.T) {
+	defer mogi.Reset()
+	db := openDB()
+
+	tb := &fakeTB{TB: t}
+	mogi.Cleanup(tb)
+	mogi.Select().From("beer").StubCSV(beerCSV)
+	mogi.Insert().Into("beer").StubResult(1, 1)
+	runBeerSelectQuery(t, db)
+
+	if len(tb.cleanups) != 1 {
+		t.Fatal("Cleanup should register one cleanup function, got", len(tb.cleanups))
+	}
+	tb.cleanups[0]()
+	expect := []string{"mogi: unused stub: INSERT (any) TABLE beer"}
+	if !reflect.DeepEqual(tb.errors, expect) {
+		t.Errorf("bad errors: %q ≠ %q", tb.errors, expect)
+	}
+	// stubs should have been reset
+	runUnstubbedSelect(t, db)
+}
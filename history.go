@@ -0,0 +1,85 @@
+package mogi
+
+import (
+	"database/sql/driver"
+	"time"
+)
+
+// Record is a query that was run, as returned by History.
+type Record struct {
+	// Time is when the query was run.
+	Time time.Time
+	// SQL is the raw query.
+	SQL string
+	// Args are the args passed to the query.
+	Args []driver.Value
+	// NamedArgs are the named args (sql.Named) passed to the query, by name.
+	NamedArgs map[string]driver.Value
+	// Kind is the type of statement: SELECT, UNION, INSERT, UPDATE, DELETE, SET, DDL, or OTHER.
+	// It is empty if the query couldn't be parsed.
+	Kind string
+
+	// Tables are the (un-aliased) tables used by the query.
+	Tables []string
+	// Cols are the columns selected, inserted, or updated.
+	Cols []string
+	// Where holds the values in the WHERE clause by column, for SELECT, UPDATE, and DELETE.
+	Where map[string]interface{}
+	// Rows holds the values inserted by column, for INSERTs.
+	Rows []map[string]interface{}
+	// Values holds the values of the SET clause by column, for UPDATEs.
+	Values map[string]interface{}
+
+	// Stub is the query stub that was matched, or nil.
+	Stub *Stub
+	// ExecStub is the exec stub that was matched, or nil.
+	ExecStub *ExecStub
+	// Table is the table the query was run against if no stub matched, or nil.
+	Table *TableStub
+
+	in *input
+}
+
+func newRecord(in *input) Record {
+	return Record{
+		Time: time.Now(),
+		SQL:  in.query,
+		Kind: in.kind(),
+		in:   in,
+	}
+}
+
+// withQuery fills in the fields taken from the query, copying them so callers can't change the input.
+// This is done when the history is read, so queries don't pay for it (or fail because of it) when run.
+func (rec Record) withQuery() Record {
+	in := rec.in
+	if in == nil {
+		return rec
+	}
+	if in.args != nil {
+		rec.Args = make([]driver.Value, len(in.args))
+		copy(rec.Args, in.args)
+	}
+	if in.named != nil {
+		rec.NamedArgs = make(map[string]driver.Value, len(in.named))
+		for k, v := range in.named {
+			rec.NamedArgs[k] = v
+		}
+	}
+	if in.statement == nil {
+		return rec
+	}
+	rec.Tables = in.tables()
+	rec.Cols = in.cols()
+	rec.Where = in.extractWhere()
+	rec.Rows = in.rows()
+	if rec.Kind == "UPDATE" {
+		rec.Values = in.values()
+	}
+	return rec
+}
+
+// Matched returns true if this query matched a stub or table.
+func (rec Record) Matched() bool {
+	return rec.Stub != nil || rec.ExecStub != nil || rec.Table != nil
+}
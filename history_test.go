@@ -0,0 +1,142 @@
+package mogi_test
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"errors"
+	"log"
+	"os"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/guregu/mogi"
+)
+
+func TestHistory(t *testing.T) {
+	defer mogi.Reset()
+	db := openDB()
+	start := time.Now()
+
+	sel := mogi.Select().From("beer")
+	sel.StubCSV(beerCSV)
+	ins := mogi.Insert().Into("beer")
+	ins.StubResult(3, 1)
+
+	runBeerSelectQuery(t, db)
+	_, err := db.Exec(`INSERT INTO beer (name, brewery, pct) VALUES (?, "Mikkeller", 4.6), (?, ?, ?)`,
+		"Mikkel’s Dream",
+		"Tokyo*", "BrewDog", 18.2,
+	)
+	checkNil(t, err)
+	_, err = db.Exec("UPDATE beer SET pct = ? WHERE id = ?", 4.7, 3)
+	if !errors.Is(err, mogi.ErrUnstubbed) {
+		t.Error("err should be ErrUnstubbed but is", err)
+	}
+	_, err = db.Exec("DELETE FROM beer WHERE id IN (?, 2)", 1)
+	if !errors.Is(err, mogi.ErrUnstubbed) {
+		t.Error("err should be ErrUnstubbed but is", err)
+	}
+
+	history := mogi.History()
+	if len(history) != 4 {
+		t.Fatal("history should have 4 records, but has", len(history))
+	}
+	for _, rec := range history {
+		if rec.Time.Before(start) || rec.Time.After(time.Now()) {
+			t.Error("bad timestamp", rec.Time)
+		}
+	}
+
+	sr := history[0]
+	checkRecord(t, sr.SQL, "SELECT id, name, brewery, pct FROM beer WHERE pct > ?")
+	checkRecord(t, sr.Args, []driver.Value{int64(5)})
+	checkRecord(t, sr.Kind, "SELECT")
+	checkRecord(t, sr.Tables, []string{"beer"})
+	checkRecord(t, sr.Cols, []string{"id", "name", "brewery", "pct"})
+	checkRecord(t, sr.Where, map[string]interface{}{"pct": int64(5)})
+	if sr.Stub != sel || sr.ExecStub != nil || !sr.Matched() {
+		t.Error("SELECT should have matched stub", sr.Stub, sr.ExecStub)
+	}
+
+	ir := history[1]
+	checkRecord(t, ir.Kind, "INSERT")
+	checkRecord(t, ir.Tables, []string{"beer"})
+	checkRecord(t, ir.Rows, []map[string]interface{}{
+		{"name": "Mikkel’s Dream", "brewery": "Mikkeller", "pct": 4.6},
+		{"name": "Tokyo*", "brewery": "BrewDog", "pct": 18.2},
+	})
+	if ir.ExecStub != ins {
+		t.Error("INSERT should have matched stub", ir.ExecStub)
+	}
+
+	ur := history[2]
+	checkRecord(t, ur.Kind, "UPDATE")
+	checkRecord(t, ur.Values, map[string]interface{}{"pct": 4.7})
+	checkRecord(t, ur.Where, map[string]interface{}{"id": int64(3)})
+	if ur.Matched() {
+		t.Error("UPDATE shouldn't have matched")
+	}
+
+	dr := history[3]
+	checkRecord(t, dr.Kind, "DELETE")
+	checkRecord(t, dr.Where, map[string]interface{}{"id": []interface{}{int64(1), int64(2)}})
+
+	mogi.Reset()
+	if len(mogi.History()) != 0 {
+		t.Error("history should be empty after reset")
+	}
+}
+
+func TestHistoryUnsupported(t *testing.T) {
+	defer mogi.Reset()
+	db := openDB()
+
+	var buf bytes.Buffer
+	log.SetOutput(&buf)
+	defer log.SetOutput(os.Stderr)
+
+	// no stubs
+	_, err := db.Exec("INSERT INTO beer (a) VALUES (SELECT 1 FROM x)")
+	if !errors.Is(err, mogi.ErrUnstubbed) {
+		t.Error("err should be ErrUnstubbed but is", err)
+	}
+	_, err = db.Exec("UPDATE beer SET pct = -pct WHERE id = -1")
+	if !errors.Is(err, mogi.ErrUnstubbed) {
+		t.Error("err should be ErrUnstubbed but is", err)
+	}
+	if buf.Len() != 0 {
+		t.Error("running queries shouldn't log:", buf.String())
+	}
+
+	history := mogi.History()
+	if len(history) != 2 {
+		t.Fatal("history should have 2 records, but has", len(history))
+	}
+	checkRecord(t, history[0].Kind, "INSERT")
+	checkRecord(t, history[0].Rows, []map[string]interface{}{{}})
+}
+
+func TestHistoryCopies(t *testing.T) {
+	defer mogi.Reset()
+	db := openDB()
+
+	mogi.Select().From("beer").Where("id", 1).StubCSV(beerCSV)
+	_, err := db.Query("SELECT id FROM beer WHERE id = ?", 1)
+	checkNil(t, err)
+
+	rec := mogi.History()[0]
+	rec.Args[0] = int64(2)
+	rec.Where["id"] = int64(2)
+
+	rec = mogi.History()[0]
+	checkRecord(t, rec.Args, []driver.Value{int64(1)})
+	checkRecord(t, rec.Where, map[string]interface{}{"id": int64(1)})
+}
+
+func checkRecord(t *testing.T, got, expect interface{}) {
+	t.Helper()
+	if !reflect.DeepEqual(got, expect) {
+		t.Errorf("bad record: %#v ≠ %#v", got, expect)
+	}
+}
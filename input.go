@@ -12,22 +12,49 @@ type input struct {
 	query     string
 	statement sqlparser.Statement
 	args      []driver.Value
+	named     map[string]driver.Value
+	tx        *driver.TxOptions // nil if not in a transaction
+	dialect   Dialect
 
 	whereVars   map[string]interface{}
 	whereOpVars map[colop]interface{}
 }
 
-func newInput(query string, args []driver.Value) (in input, err error) {
-	in = input{
-		query: query,
-		args:  args,
+// input is a parsed query. It is not safe for concurrent use,
+// each query gets its own input.
+func newInput(dialect Dialect, query string, args []driver.NamedValue) (in *input, err error) {
+	in = &input{
+		query:   query,
+		args:    make([]driver.Value, len(args)),
+		dialect: dialect,
 	}
-	in.statement, err = sqlparser.Parse(query)
+	for i, arg := range args {
+		in.args[i] = arg.Value
+		if arg.Name != "" {
+			if in.named == nil {
+				in.named = make(map[string]driver.Value)
+			}
+			in.named[arg.Name] = arg.Value
+		}
+	}
+	in.statement, err = sqlparser.Parse(dialect.translate(query))
 	return
 }
 
+// arg is a positional placeholder (?), starting from 0.
 type arg int
 
+// namedArg is a named placeholder, including its prefix (:name or @name).
+type namedArg string
+
+func (na namedArg) name() string {
+	return string(na[1:])
+}
+
+func (na namedArg) String() string {
+	return string(na)
+}
+
 type opval struct {
 	op string
 	v  interface{}
@@ -44,15 +71,12 @@ SELECT a        → a
 SELECT a.b      → a.b
 SELECT a.b AS c → c
 */
-func (in input) cols() []string {
+func (in *input) cols() []string {
 	var cols []string
 
 	switch x := in.statement.(type) {
 	case *sqlparser.Select:
-		for _, sexpr := range x.SelectExprs {
-			name := stringify(transmogrify(sexpr))
-			cols = append(cols, name)
-		}
+		cols = selectExprNames(x.SelectExprs)
 	case *sqlparser.Insert:
 		for _, c := range x.Columns {
 			nse, ok := c.(*sqlparser.NonStarExpr)
@@ -73,8 +97,30 @@ func (in input) cols() []string {
 	return cols
 }
 
+// returning returns the columns of the RETURNING clause, using the same rules as cols.
+func (in *input) returning() []string {
+	switch x := in.statement.(type) {
+	case *sqlparser.Insert:
+		return selectExprNames(sqlparser.SelectExprs(x.Returning))
+	case *sqlparser.Update:
+		return selectExprNames(sqlparser.SelectExprs(x.Returning))
+	case *sqlparser.Delete:
+		return selectExprNames(sqlparser.SelectExprs(x.Returning))
+	}
+	return nil
+}
+
+func selectExprNames(exprs sqlparser.SelectExprs) []string {
+	var cols []string
+	for _, sexpr := range exprs {
+		name := stringify(transmogrify(sexpr))
+		cols = append(cols, name)
+	}
+	return cols
+}
+
 // for UPDATEs
-func (in input) values() map[string]interface{} {
+func (in *input) values() map[string]interface{} {
 	vals := make(map[string]interface{})
 
 	switch x := in.statement.(type) {
@@ -82,12 +128,7 @@ func (in input) values() map[string]interface{} {
 		for _, expr := range x.Exprs {
 			// TODO qualifiers
 			colName := string(expr.Name.Name)
-			v := transmogrify(expr.Expr)
-			if a, ok := v.(arg); ok {
-				// replace placeholders
-				v = unify(in.args[int(a)])
-			}
-			vals[colName] = v
+			vals[colName] = in.resolve(transmogrify(expr.Expr))
 		}
 	}
 
@@ -95,84 +136,92 @@ func (in input) values() map[string]interface{} {
 }
 
 // for INSERTs
-func (in input) rows() []map[string]interface{} {
+func (in *input) rows() []map[string]interface{} {
 	var vals []map[string]interface{}
 	cols := in.cols()
 
 	switch x := in.statement.(type) {
 	case *sqlparser.Insert:
-		insertRows := x.Rows.(sqlparser.Values)
+		insertRows, ok := x.Rows.(sqlparser.Values)
+		if !ok {
+			// INSERT ... SELECT
+			return nil
+		}
 		vals = make([]map[string]interface{}, len(insertRows))
 		for i, rowTuple := range insertRows {
 			vals[i] = make(map[string]interface{})
-			row := rowTuple.(sqlparser.ValTuple)
+			row, ok := rowTuple.(sqlparser.ValTuple)
+			if !ok {
+				// subquery
+				continue
+			}
 			for j, val := range row {
-				colName := cols[j]
-				v := transmogrify(val)
-				if a, ok := v.(arg); ok {
-					// replace placeholders
-					v = unify(in.args[int(a)])
+				if j >= len(cols) {
+					break
 				}
-				vals[i][colName] = v
+				vals[i][cols[j]] = in.resolve(transmogrify(val))
 			}
 		}
 	}
 	return vals
 }
 
-// for SELECT and UPDATE and DELETE
-func (in input) where() map[string]interface{} {
-	if in.whereVars != nil {
-		return in.whereVars
-	}
-	var w *sqlparser.Where
+// whereClause returns the WHERE clause of a SELECT, UPDATE, or DELETE, which can be nil.
+// ok is false for other kinds of statements.
+func (in *input) whereClause() (w *sqlparser.Where, ok bool) {
 	switch x := in.statement.(type) {
 	case *sqlparser.Select:
-		w = x.Where
+		return x.Where, true
 	case *sqlparser.Update:
-		w = x.Where
+		return x.Where, true
 	case *sqlparser.Delete:
-		w = x.Where
-	default:
+		return x.Where, true
+	}
+	return nil, false
+}
+
+// satisfies returns true if the given row would satisfy the WHERE clause.
+// Queries without a WHERE clause are satisfied by any row.
+func (in *input) satisfies(cols []string, row []driver.Value) (bool, error) {
+	w, ok := in.whereClause()
+	if !ok {
+		return false, nil
+	}
+	return evaluator{in: in, cols: cols, row: row}.satisfies(w)
+}
+
+// for SELECT and UPDATE and DELETE
+func (in *input) where() map[string]interface{} {
+	if in.whereVars == nil {
+		in.whereVars = in.extractWhere()
+	}
+	return in.whereVars
+}
+
+// extractWhere is like where, but returns a new map every time.
+func (in *input) extractWhere() map[string]interface{} {
+	w, ok := in.whereClause()
+	if !ok {
 		return nil
 	}
 	if w == nil {
 		return map[string]interface{}{}
 	}
-	in.whereVars = extractBoolExpr(nil, w.Expr)
+	vals := extractBoolExpr(nil, w.Expr)
 	// replace placeholders
-	for k, v := range in.whereVars {
-		if a, ok := v.(arg); ok {
-			in.whereVars[k] = unify(in.args[int(a)])
-			continue
-		}
-
-		// arrays
-		if arr, ok := v.([]interface{}); ok {
-			for i, v := range arr {
-				if a, ok := v.(arg); ok {
-					arr[i] = unify(in.args[int(a)])
-				}
-			}
-		}
+	for k, v := range vals {
+		vals[k] = in.resolve(v)
 	}
-	return in.whereVars
+	return vals
 }
 
 // for SELECT and UPDATE and DELETE
-func (in input) whereOp() map[colop]interface{} {
+func (in *input) whereOp() map[colop]interface{} {
 	if in.whereOpVars != nil {
 		return in.whereOpVars
 	}
-	var w *sqlparser.Where
-	switch x := in.statement.(type) {
-	case *sqlparser.Select:
-		w = x.Where
-	case *sqlparser.Update:
-		w = x.Where
-	case *sqlparser.Delete:
-		w = x.Where
-	default:
+	w, ok := in.whereClause()
+	if !ok {
 		return nil
 	}
 	if w == nil {
@@ -181,19 +230,76 @@ func (in input) whereOp() map[colop]interface{} {
 	in.whereOpVars = extractBoolExprWithOps(nil, w.Expr)
 	// replace placeholders
 	for k, v := range in.whereOpVars {
-		if a, ok := v.(arg); ok {
-			in.whereOpVars[k] = unify(in.args[int(a)])
-			continue
-		}
+		in.whereOpVars[k] = in.resolve(v)
+	}
+	return in.whereOpVars
+}
 
-		// arrays
-		if arr, ok := v.([]interface{}); ok {
-			for i, v := range arr {
-				if a, ok := v.(arg); ok {
-					arr[i] = unify(in.args[int(a)])
-				}
+// resolve replaces placeholders in v (and arrays of v) with the args given to the query.
+// Named placeholders (:name and @name) are replaced by named args (sql.Named).
+func (in *input) resolve(v interface{}) interface{} {
+	switch x := v.(type) {
+	case arg:
+		if int(x) >= len(in.args) {
+			return nil
+		}
+		return unify(in.args[int(x)])
+	case namedArg:
+		v, ok := in.named[x.name()]
+		if !ok {
+			if x[0] == '@' {
+				// probably a MySQL user variable
+				return x.String()
 			}
+			return nil
+		}
+		return unify(v)
+	case []interface{}:
+		for i, item := range x {
+			x[i] = in.resolve(item)
 		}
 	}
-	return in.whereOpVars
+	return v
+}
+
+// kind returns the type of statement, such as "SELECT" or "INSERT".
+func (in *input) kind() string {
+	switch in.statement.(type) {
+	case *sqlparser.Select:
+		return "SELECT"
+	case *sqlparser.Union:
+		return "UNION"
+	case *sqlparser.Insert:
+		return "INSERT"
+	case *sqlparser.Update:
+		return "UPDATE"
+	case *sqlparser.Delete:
+		return "DELETE"
+	case *sqlparser.Set:
+		return "SET"
+	case *sqlparser.DDL:
+		return "DDL"
+	case *sqlparser.Other:
+		return "OTHER"
+	}
+	return ""
+}
+
+// tables returns the un-aliased table names used by the query.
+// For SELECTs, these are the tables in the FROM and JOIN clauses (in order).
+func (in *input) tables() []string {
+	var tables []string
+	switch x := in.statement.(type) {
+	case *sqlparser.Select:
+		for _, tex := range x.From {
+			extractTableNames(&tables, tex)
+		}
+	case *sqlparser.Insert:
+		tables = append(tables, string(x.Table.Name))
+	case *sqlparser.Update:
+		tables = append(tables, string(x.Table.Name))
+	case *sqlparser.Delete:
+		tables = append(tables, string(x.Table.Name))
+	}
+	return tables
 }
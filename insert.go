@@ -12,7 +12,7 @@ type insertCond struct {
 	cols []string
 }
 
-func (ic insertCond) matches(in input) bool {
+func (ic insertCond) matches(in *input) bool {
 	_, ok := in.statement.(*sqlparser.Insert)
 	if !ok {
 		return false
@@ -1,6 +1,7 @@
 package mogi_test
 
 import (
+	"errors"
 	"testing"
 
 	"github.com/guregu/mogi"
@@ -26,7 +27,7 @@ func TestInsert(t *testing.T) {
 	mogi.Reset()
 	mogi.Insert("犬", "🐱", "かっぱ").Into("beer").StubResult(3, 1)
 	_, err = db.Exec("INSERT INTO beer (name, brewery, pct) VALUES (?, ?, ?)", "Mikkel’s Dream", "Mikkeller", 4.6)
-	if err != mogi.ErrUnstubbed {
+	if !errors.Is(err, mogi.ErrUnstubbed) {
 		t.Error("err should be ErrUnstubbed but is", err)
 	}
 }
@@ -43,7 +44,7 @@ func TestInsertArgs(t *testing.T) {
 	mogi.Reset()
 	mogi.Insert().Args("Nodogoshi", "Kirin", 5).StubResult(4, 1)
 	_, err = db.Exec("INSERT INTO beer (name, brewery, pct) VALUES (?, ?, ?)", "Mikkel’s Dream", "Mikkeller", 4.6)
-	if err != mogi.ErrUnstubbed {
+	if !errors.Is(err, mogi.ErrUnstubbed) {
 		t.Error("err should be ErrUnstubbed but is", err)
 	}
 }
@@ -244,19 +244,20 @@ func (node *Union) WalkSubtree(visit Visit) error {
 
 // Insert represents an INSERT statement.
 type Insert struct {
-	Comments Comments
-	Ignore   string
-	Table    *TableName
-	Columns  Columns
-	Rows     InsertRows
-	OnDup    OnDup
+	Comments  Comments
+	Ignore    string
+	Table     *TableName
+	Columns   Columns
+	Rows      InsertRows
+	OnDup     OnDup
+	Returning Returning
 }
 
 // Format formats the node.
 func (node *Insert) Format(buf *TrackedBuffer) {
-	buf.Myprintf("insert %v%sinto %v%v %v%v",
+	buf.Myprintf("insert %v%sinto %v%v %v%v%v",
 		node.Comments, node.Ignore,
-		node.Table, node.Columns, node.Rows, node.OnDup)
+		node.Table, node.Columns, node.Rows, node.OnDup, node.Returning)
 }
 
 // WalkSubtree walks the nodes of the subtree
@@ -271,6 +272,7 @@ func (node *Insert) WalkSubtree(visit Visit) error {
 		node.Columns,
 		node.Rows,
 		node.OnDup,
+		node.Returning,
 	)
 }
 
@@ -286,19 +288,20 @@ func (Values) iInsertRows()  {}
 
 // Update represents an UPDATE statement.
 type Update struct {
-	Comments Comments
-	Table    *TableName
-	Exprs    UpdateExprs
-	Where    *Where
-	OrderBy  OrderBy
-	Limit    *Limit
+	Comments  Comments
+	Table     *TableName
+	Exprs     UpdateExprs
+	Where     *Where
+	OrderBy   OrderBy
+	Limit     *Limit
+	Returning Returning
 }
 
 // Format formats the node.
 func (node *Update) Format(buf *TrackedBuffer) {
-	buf.Myprintf("update %v%v set %v%v%v%v",
+	buf.Myprintf("update %v%v set %v%v%v%v%v",
 		node.Comments, node.Table,
-		node.Exprs, node.Where, node.OrderBy, node.Limit)
+		node.Exprs, node.Where, node.OrderBy, node.Limit, node.Returning)
 }
 
 // WalkSubtree walks the nodes of the subtree
@@ -314,23 +317,25 @@ func (node *Update) WalkSubtree(visit Visit) error {
 		node.Where,
 		node.OrderBy,
 		node.Limit,
+		node.Returning,
 	)
 }
 
 // Delete represents a DELETE statement.
 type Delete struct {
-	Comments Comments
-	Table    *TableName
-	Where    *Where
-	OrderBy  OrderBy
-	Limit    *Limit
+	Comments  Comments
+	Table     *TableName
+	Where     *Where
+	OrderBy   OrderBy
+	Limit     *Limit
+	Returning Returning
 }
 
 // Format formats the node.
 func (node *Delete) Format(buf *TrackedBuffer) {
-	buf.Myprintf("delete %vfrom %v%v%v%v",
+	buf.Myprintf("delete %vfrom %v%v%v%v%v",
 		node.Comments,
-		node.Table, node.Where, node.OrderBy, node.Limit)
+		node.Table, node.Where, node.OrderBy, node.Limit, node.Returning)
 }
 
 // WalkSubtree walks the nodes of the subtree
@@ -345,6 +350,7 @@ func (node *Delete) WalkSubtree(visit Visit) error {
 		node.Where,
 		node.OrderBy,
 		node.Limit,
+		node.Returning,
 	)
 }
 
@@ -1540,6 +1546,7 @@ func (node OrderBy) WalkSubtree(visit Visit) error {
 type Order struct {
 	Expr      ValExpr
 	Direction string
+	Nulls     string
 }
 
 // Order.Direction
@@ -1548,9 +1555,18 @@ const (
 	DescScr = "desc"
 )
 
+// Order.Nulls
+const (
+	NullsFirstStr = "nulls first"
+	NullsLastStr  = "nulls last"
+)
+
 // Format formats the node.
 func (node *Order) Format(buf *TrackedBuffer) {
 	buf.Myprintf("%v %s", node.Expr, node.Direction)
+	if node.Nulls != "" {
+		buf.Myprintf(" %s", node.Nulls)
+	}
 }
 
 // WalkSubtree walks the nodes of the subtree
@@ -1564,7 +1580,7 @@ func (node *Order) WalkSubtree(visit Visit) error {
 	)
 }
 
-// Limit represents a LIMIT clause.
+// Limit represents a LIMIT clause, or an OFFSET clause without a LIMIT (Rowcount is nil).
 type Limit struct {
 	Offset, Rowcount ValExpr
 }
@@ -1574,6 +1590,10 @@ func (node *Limit) Format(buf *TrackedBuffer) {
 	if node == nil {
 		return
 	}
+	if node.Rowcount == nil {
+		buf.Myprintf(" offset %v", node.Offset)
+		return
+	}
 	buf.Myprintf(" limit ")
 	if node.Offset != nil {
 		buf.Myprintf("%v, ", node.Offset)
@@ -1728,6 +1748,22 @@ func (node OnDup) WalkSubtree(visit Visit) error {
 	return Walk(visit, UpdateExprs(node))
 }
 
+// Returning represents a RETURNING clause.
+type Returning SelectExprs
+
+// Format formats the node.
+func (node Returning) Format(buf *TrackedBuffer) {
+	if node == nil {
+		return
+	}
+	buf.Myprintf(" returning %v", SelectExprs(node))
+}
+
+// WalkSubtree walks the nodes of the subtree
+func (node Returning) WalkSubtree(visit Visit) error {
+	return Walk(visit, SelectExprs(node))
+}
+
 // SQLName is an SQL identifier. It will be escaped with
 // backquotes if it matches a keyword.
 type SQLName string
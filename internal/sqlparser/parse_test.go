@@ -374,10 +374,51 @@ func TestValid(t *testing.T) {
 		input: "select /* order by asc */ 1 from t order by a asc",
 	}, {
 		input: "select /* order by desc */ 1 from t order by a desc",
+	}, {
+		input:  "select /* order by nulls */ 1 from t order by a desc nulls last, b nulls first",
+		output: "select /* order by nulls */ 1 from t order by a desc nulls last, b asc nulls first",
+	}, {
+		input:  "select /* nulls first last columns */ nulls, first, last from t order by last",
+		output: "select /* nulls first last columns */ `nulls`, `first`, `last` from t order by `last` asc",
+	}, {
+		input:  "select /* last alias */ a from t as last",
+		output: "select /* last alias */ a from t as `last`",
+	}, {
+		input:  "select /* last bare alias */ a from t last",
+		output: "select /* last bare alias */ a from t as `last`",
+	}, {
+		input:  "select /* first qualifier */ first.a from t as first",
+		output: "select /* first qualifier */ `first`.a from t as `first`",
+	}, {
+		input:  "select /* first table */ a from first",
+		output: "select /* first table */ a from `first`",
+	}, {
+		input:  "select /* nulls table */ a from nulls nulls order by a nulls first",
+		output: "select /* nulls table */ a from `nulls` as `nulls` order by a asc nulls first",
+	}, {
+		input:  "insert /* last table */ into last (a) values (1)",
+		output: "insert /* last table */ into `last`(a) values (1)",
 	}, {
 		input: "select /* limit a */ 1 from t limit a",
 	}, {
 		input: "select /* limit a,b */ 1 from t limit a, b",
+	}, {
+		input:  "select /* limit offset */ 1 from t limit a offset b",
+		output: "select /* limit offset */ 1 from t limit b, a",
+	}, {
+		input: "select /* offset */ id from t offset 10",
+	}, {
+		input:  "select /* offset limit */ id from t offset 10 limit 5",
+		output: "select /* offset limit */ id from t limit 10, 5",
+	}, {
+		input:  "select /* offset table */ a from offset as offset offset 1",
+		output: "select /* offset table */ a from `offset` as `offset` offset 1",
+	}, {
+		input:  "update /* offset table */ offset set a = 1",
+		output: "update /* offset table */ `offset` set a = 1",
+	}, {
+		input:  "select /* offset column */ offset from t where offset = 1 limit 1 offset 2",
+		output: "select /* offset column */ `offset` from t where `offset` = 1 limit 2, 1",
 	}, {
 		input:  "select /* binary unary */ a- -b from t",
 		output: "select /* binary unary */ a - -b from t",
@@ -440,6 +481,36 @@ func TestValid(t *testing.T) {
 		input: "delete /* order */ from a order by b desc",
 	}, {
 		input: "delete /* limit */ from a limit b",
+	}, {
+		input: "insert /* returning */ into a(b, c) values (1, 2) returning a, b as c",
+	}, {
+		input:  "insert /* returning set */ into a set b = 1 returning *",
+		output: "insert /* returning set */ into a(b) values (1) returning *",
+	}, {
+		input: "update /* returning */ a set b = 3 where c = 1 returning a.*, b",
+	}, {
+		input: "delete /* returning */ from a where b = 1 returning id",
+	}, {
+		input:  "select /* returning column */ returning from t where returning = 1",
+		output: "select /* returning column */ `returning` from t where `returning` = 1",
+	}, {
+		input:  "update /* returning column */ a set returning = 1 where returning = 2 returning returning",
+		output: "update /* returning column */ a set `returning` = 1 where `returning` = 2 returning `returning`",
+	}, {
+		input:  "insert /* returning column */ into a(returning) values (1) returning returning",
+		output: "insert /* returning column */ into a(`returning`) values (1) returning `returning`",
+	}, {
+		input:  "select /* returning table */ a from returning",
+		output: "select /* returning table */ a from `returning`",
+	}, {
+		input:  "select /* returning alias */ returning.a from t as returning",
+		output: "select /* returning alias */ `returning`.a from t as `returning`",
+	}, {
+		input:  "update /* returning table */ returning set a = 1",
+		output: "update /* returning table */ `returning` set a = 1",
+	}, {
+		input:  "delete /* returning table */ from returning where a = 1 returning a",
+		output: "delete /* returning table */ from `returning` where a = 1 returning a",
 	}, {
 		input: "set /* simple */ a = 3",
 	}, {
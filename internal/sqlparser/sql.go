@@ -1,9 +1,12 @@
+// Code generated by goyacc -o sql.go sql.y. DO NOT EDIT.
+
 //line sql.y:6
 package sqlparser
 
 import __yyfmt__ "fmt"
 
 //line sql.y:6
+
 import "strings"
 
 func setParseTree(yylex interface{}, stmt Statement) {
@@ -86,81 +89,86 @@ const HAVING = 57358
 const ORDER = 57359
 const BY = 57360
 const LIMIT = 57361
-const FOR = 57362
-const ALL = 57363
-const DISTINCT = 57364
-const AS = 57365
-const EXISTS = 57366
-const ASC = 57367
-const DESC = 57368
-const INTO = 57369
-const DUPLICATE = 57370
-const KEY = 57371
-const DEFAULT = 57372
-const SET = 57373
-const LOCK = 57374
-const KEYRANGE = 57375
-const VALUES = 57376
-const LAST_INSERT_ID = 57377
-const NEXT = 57378
-const VALUE = 57379
-const JOIN = 57380
-const STRAIGHT_JOIN = 57381
-const LEFT = 57382
-const RIGHT = 57383
-const INNER = 57384
-const OUTER = 57385
-const CROSS = 57386
-const NATURAL = 57387
-const USE = 57388
-const FORCE = 57389
-const ON = 57390
-const ID = 57391
-const STRING = 57392
-const NUMBER = 57393
-const VALUE_ARG = 57394
-const LIST_ARG = 57395
-const COMMENT = 57396
-const NULL = 57397
-const TRUE = 57398
-const FALSE = 57399
-const OR = 57400
-const AND = 57401
-const NOT = 57402
-const BETWEEN = 57403
-const CASE = 57404
-const WHEN = 57405
-const THEN = 57406
-const ELSE = 57407
-const LE = 57408
-const GE = 57409
-const NE = 57410
-const NULL_SAFE_EQUAL = 57411
-const IS = 57412
-const LIKE = 57413
-const REGEXP = 57414
-const IN = 57415
-const SHIFT_LEFT = 57416
-const SHIFT_RIGHT = 57417
-const UNARY = 57418
-const INTERVAL = 57419
-const END = 57420
-const CREATE = 57421
-const ALTER = 57422
-const DROP = 57423
-const RENAME = 57424
-const ANALYZE = 57425
-const TABLE = 57426
-const INDEX = 57427
-const VIEW = 57428
-const TO = 57429
-const IGNORE = 57430
-const IF = 57431
-const UNIQUE = 57432
-const USING = 57433
-const SHOW = 57434
-const DESCRIBE = 57435
-const EXPLAIN = 57436
+const OFFSET = 57362
+const FOR = 57363
+const ALL = 57364
+const DISTINCT = 57365
+const AS = 57366
+const EXISTS = 57367
+const ASC = 57368
+const DESC = 57369
+const NULLS = 57370
+const FIRST = 57371
+const LAST = 57372
+const INTO = 57373
+const DUPLICATE = 57374
+const KEY = 57375
+const DEFAULT = 57376
+const SET = 57377
+const LOCK = 57378
+const KEYRANGE = 57379
+const VALUES = 57380
+const LAST_INSERT_ID = 57381
+const RETURNING = 57382
+const NEXT = 57383
+const VALUE = 57384
+const JOIN = 57385
+const STRAIGHT_JOIN = 57386
+const LEFT = 57387
+const RIGHT = 57388
+const INNER = 57389
+const OUTER = 57390
+const CROSS = 57391
+const NATURAL = 57392
+const USE = 57393
+const FORCE = 57394
+const ON = 57395
+const ID = 57396
+const STRING = 57397
+const NUMBER = 57398
+const VALUE_ARG = 57399
+const LIST_ARG = 57400
+const COMMENT = 57401
+const NULL = 57402
+const TRUE = 57403
+const FALSE = 57404
+const OR = 57405
+const AND = 57406
+const NOT = 57407
+const BETWEEN = 57408
+const CASE = 57409
+const WHEN = 57410
+const THEN = 57411
+const ELSE = 57412
+const LE = 57413
+const GE = 57414
+const NE = 57415
+const NULL_SAFE_EQUAL = 57416
+const IS = 57417
+const LIKE = 57418
+const REGEXP = 57419
+const IN = 57420
+const SHIFT_LEFT = 57421
+const SHIFT_RIGHT = 57422
+const UNARY = 57423
+const INTERVAL = 57424
+const END = 57425
+const CREATE = 57426
+const ALTER = 57427
+const DROP = 57428
+const RENAME = 57429
+const ANALYZE = 57430
+const TABLE = 57431
+const INDEX = 57432
+const VIEW = 57433
+const TO = 57434
+const IGNORE = 57435
+const IF = 57436
+const UNIQUE = 57437
+const USING = 57438
+const SHOW = 57439
+const DESCRIBE = 57440
+const EXPLAIN = 57441
 
 var yyToknames = [...]string{
 	"$end",
@@ -182,6 +190,7 @@ var yyToknames = [...]string{
 	"ORDER",
 	"BY",
 	"LIMIT",
+	"OFFSET",
 	"FOR",
 	"ALL",
 	"DISTINCT",
@@ -189,6 +198,9 @@ var yyToknames = [...]string{
 	"EXISTS",
 	"ASC",
 	"DESC",
+	"NULLS",
+	"FIRST",
+	"LAST",
 	"INTO",
 	"DUPLICATE",
 	"KEY",
@@ -198,6 +210,7 @@ var yyToknames = [...]string{
 	"KEYRANGE",
 	"VALUES",
 	"LAST_INSERT_ID",
+	"RETURNING",
 	"NEXT",
 	"VALUE",
 	"JOIN",
@@ -274,6 +287,7 @@ var yyToknames = [...]string{
 	"DESCRIBE",
 	"EXPLAIN",
 }
+
 var yyStatenames = [...]string{}
 
 const yyEofCode = 1
@@ -281,201 +295,218 @@ const yyErrCode = 2
 const yyInitialStackSize = 16
 
 //line yacctab:1
-var yyExca = [...]int{
+var yyExca = [...]int16{
 	-1, 1,
 	1, -1,
 	-2, 0,
-	-1, 69,
-	93, 222,
-	-2, 221,
+	-1, 76,
+	98, 237,
+	-2, 229,
+	-1, 77,
+	98, 238,
+	-2, 230,
 }
 
-const yyNprod = 226
 const yyPrivate = 57344
 
-var yyTokenNames []string
-var yyStates []string
-
-const yyLast = 863
-
-var yyAct = [...]int{
-
-	100, 323, 168, 405, 94, 271, 368, 95, 64, 171,
-	358, 282, 212, 229, 93, 210, 211, 170, 3, 251,
-	264, 214, 83, 223, 65, 191, 60, 84, 199, 79,
-	107, 343, 345, 69, 108, 109, 110, 71, 67, 111,
-	44, 73, 66, 278, 76, 53, 113, 38, 43, 40,
-	44, 129, 380, 41, 46, 47, 48, 114, 89, 379,
-	378, 72, 75, 49, 45, 96, 97, 355, 297, 139,
-	50, 98, 88, 99, 112, 153, 154, 155, 156, 157,
-	152, 122, 133, 118, 152, 419, 101, 137, 142, 344,
-	119, 155, 156, 157, 152, 172, 51, 52, 387, 173,
-	175, 176, 177, 140, 121, 265, 205, 150, 158, 159,
-	153, 154, 155, 156, 157, 152, 184, 142, 67, 203,
-	124, 67, 66, 195, 194, 66, 189, 115, 151, 150,
-	158, 159, 153, 154, 155, 156, 157, 152, 89, 219,
-	195, 206, 74, 188, 126, 193, 228, 128, 74, 237,
-	238, 239, 69, 241, 242, 243, 244, 245, 246, 247,
-	248, 249, 250, 218, 167, 169, 390, 391, 141, 140,
-	265, 62, 314, 240, 357, 125, 220, 256, 257, 293,
-	180, 89, 89, 142, 80, 202, 204, 201, 252, 107,
-	253, 255, 414, 252, 196, 252, 232, 262, 258, 120,
-	275, 259, 261, 236, 209, 141, 140, 266, 62, 215,
-	298, 299, 300, 221, 222, 270, 234, 235, 233, 231,
-	142, 151, 150, 158, 159, 153, 154, 155, 156, 157,
-	152, 107, 296, 256, 62, 14, 279, 303, 304, 305,
-	301, 141, 140, 138, 174, 364, 252, 302, 158, 159,
-	153, 154, 155, 156, 157, 152, 142, 307, 134, 224,
-	226, 227, 192, 89, 225, 273, 135, 252, 254, 67,
-	67, 276, 74, 66, 321, 107, 322, 319, 62, 308,
-	359, 310, 120, 309, 313, 318, 192, 108, 109, 110,
-	388, 215, 111, 385, 331, 135, 333, 330, 280, 332,
-	92, 311, 341, 28, 29, 30, 31, 107, 231, 352,
-	254, 252, 280, 252, 268, 78, 348, 356, 374, 359,
-	377, 350, 120, 274, 365, 361, 354, 366, 369, 353,
-	362, 376, 107, 132, 338, 335, 92, 92, 315, 339,
-	363, 340, 336, 288, 289, 178, 179, 337, 334, 252,
-	181, 182, 57, 14, 381, 215, 215, 215, 215, 411,
-	383, 42, 117, 400, 81, 67, 56, 54, 384, 386,
-	116, 412, 197, 131, 382, 295, 256, 392, 317, 216,
-	92, 187, 324, 394, 373, 92, 92, 325, 186, 230,
-	402, 369, 272, 401, 404, 403, 59, 370, 406, 406,
-	406, 372, 407, 408, 329, 284, 287, 288, 289, 285,
-	67, 286, 290, 68, 66, 420, 192, 63, 417, 418,
-	421, 409, 422, 92, 92, 413, 14, 415, 416, 28,
-	29, 30, 31, 269, 33, 1, 294, 92, 291, 136,
-	198, 39, 277, 200, 70, 393, 185, 395, 396, 61,
-	284, 287, 288, 289, 285, 320, 286, 290, 267, 77,
-	375, 216, 410, 82, 389, 367, 371, 328, 312, 87,
-	183, 260, 263, 105, 102, 360, 316, 61, 230, 143,
-	90, 342, 106, 283, 123, 281, 213, 86, 55, 127,
-	27, 58, 130, 13, 12, 11, 10, 9, 107, 32,
-	252, 69, 108, 109, 110, 92, 8, 111, 103, 104,
-	92, 7, 91, 6, 113, 34, 35, 36, 37, 5,
-	4, 2, 105, 0, 0, 216, 216, 216, 216, 0,
-	61, 106, 190, 96, 97, 85, 0, 0, 0, 98,
-	0, 99, 0, 207, 0, 0, 208, 107, 217, 87,
-	69, 108, 109, 110, 101, 0, 111, 103, 104, 0,
-	0, 91, 14, 113, 0, 14, 15, 16, 17, 0,
-	0, 0, 0, 0, 0, 0, 0, 105, 0, 0,
-	0, 0, 96, 97, 85, 0, 106, 18, 98, 0,
-	99, 0, 87, 87, 0, 0, 0, 0, 0, 217,
-	0, 0, 107, 101, 0, 69, 108, 109, 110, 0,
-	0, 111, 103, 104, 0, 0, 91, 92, 113, 92,
-	92, 0, 0, 397, 398, 399, 0, 0, 292, 0,
-	217, 0, 0, 0, 0, 0, 0, 96, 97, 0,
-	0, 0, 0, 98, 0, 99, 0, 0, 0, 0,
-	0, 19, 20, 22, 21, 23, 0, 0, 101, 0,
-	0, 0, 0, 0, 24, 25, 26, 0, 0, 0,
-	0, 0, 0, 0, 87, 0, 0, 0, 0, 0,
-	0, 0, 0, 0, 105, 0, 14, 0, 326, 0,
-	0, 327, 0, 106, 217, 217, 217, 217, 0, 0,
-	0, 0, 0, 0, 0, 0, 0, 346, 347, 107,
-	0, 349, 69, 108, 109, 110, 0, 0, 111, 103,
-	104, 0, 0, 91, 0, 113, 107, 0, 0, 69,
-	108, 109, 110, 0, 0, 111, 0, 0, 0, 0,
-	0, 0, 113, 0, 96, 97, 0, 0, 0, 0,
-	98, 0, 99, 0, 0, 0, 0, 0, 0, 0,
-	0, 96, 97, 0, 0, 101, 0, 98, 0, 99,
-	0, 0, 0, 0, 0, 0, 0, 145, 148, 0,
-	0, 0, 101, 160, 161, 162, 163, 164, 165, 166,
-	149, 146, 147, 144, 151, 150, 158, 159, 153, 154,
-	155, 156, 157, 152, 351, 0, 0, 0, 0, 0,
-	0, 0, 0, 0, 306, 74, 0, 0, 0, 0,
-	0, 0, 151, 150, 158, 159, 153, 154, 155, 156,
-	157, 152, 151, 150, 158, 159, 153, 154, 155, 156,
-	157, 152, 0, 151, 150, 158, 159, 153, 154, 155,
-	156, 157, 152, 151, 150, 158, 159, 153, 154, 155,
-	156, 157, 152,
+const yyLast = 1013
+
+var yyAct = [...]int16{
+	77, 334, 71, 104, 109, 180, 430, 378, 383, 280,
+	370, 92, 50, 291, 221, 273, 93, 223, 220, 232,
+	103, 238, 219, 179, 3, 102, 260, 66, 72, 200,
+	60, 121, 208, 88, 355, 357, 63, 79, 51, 52,
+	44, 83, 74, 287, 83, 81, 63, 38, 85, 40,
+	63, 53, 43, 41, 44, 138, 395, 83, 73, 394,
+	393, 123, 46, 47, 48, 80, 84, 49, 45, 367,
+	308, 63, 148, 131, 177, 127, 161, 446, 151, 63,
+	214, 164, 165, 166, 161, 63, 130, 274, 63, 325,
+	274, 83, 356, 134, 135, 142, 83, 212, 137, 133,
+	146, 128, 162, 163, 164, 165, 166, 161, 245, 97,
+	160, 159, 167, 168, 162, 163, 164, 165, 166, 161,
+	215, 243, 244, 242, 149, 89, 63, 124, 63, 198,
+	98, 74, 83, 116, 74, 389, 204, 261, 151, 63,
+	241, 371, 63, 129, 63, 205, 189, 73, 83, 83,
+	73, 116, 228, 204, 143, 218, 197, 201, 202, 441,
+	261, 227, 150, 149, 211, 213, 210, 167, 168, 162,
+	163, 164, 165, 166, 161, 224, 181, 151, 249, 201,
+	182, 184, 185, 186, 65, 240, 129, 83, 309, 310,
+	311, 266, 67, 68, 69, 63, 144, 193, 289, 268,
+	270, 376, 261, 263, 64, 203, 262, 264, 144, 83,
+	176, 178, 275, 284, 267, 183, 409, 271, 401, 98,
+	129, 82, 285, 144, 261, 322, 63, 237, 279, 371,
+	246, 247, 248, 392, 250, 251, 252, 253, 254, 255,
+	256, 257, 258, 259, 283, 312, 150, 149, 307, 288,
+	141, 304, 369, 263, 261, 229, 277, 224, 265, 230,
+	231, 151, 98, 98, 313, 233, 235, 236, 289, 261,
+	234, 101, 391, 347, 240, 116, 28, 29, 30, 31,
+	330, 320, 74, 74, 63, 346, 57, 63, 324, 333,
+	63, 63, 63, 63, 87, 319, 350, 321, 73, 332,
+	329, 351, 379, 63, 56, 343, 63, 345, 342, 63,
+	344, 282, 126, 353, 265, 42, 101, 101, 314, 315,
+	316, 224, 224, 224, 224, 187, 188, 261, 348, 421,
+	190, 191, 373, 349, 360, 377, 400, 125, 318, 362,
+	438, 374, 261, 90, 98, 435, 436, 365, 366, 428,
+	59, 385, 150, 149, 206, 439, 306, 302, 375, 225,
+	101, 67, 68, 69, 140, 101, 101, 151, 54, 239,
+	117, 118, 119, 14, 352, 120, 297, 298, 399, 65,
+	388, 74, 402, 147, 326, 404, 337, 67, 68, 69,
+	303, 405, 364, 397, 415, 281, 413, 403, 196, 64,
+	368, 341, 328, 101, 101, 422, 195, 335, 336, 387,
+	380, 381, 384, 278, 201, 429, 82, 101, 426, 83,
+	83, 83, 70, 431, 431, 431, 432, 433, 445, 434,
+	75, 28, 29, 30, 31, 33, 14, 444, 396, 74,
+	1, 225, 83, 305, 398, 300, 447, 83, 301, 83,
+	145, 448, 207, 449, 98, 73, 39, 440, 239, 442,
+	443, 286, 209, 265, 78, 194, 61, 159, 167, 168,
+	162, 163, 164, 165, 166, 161, 86, 331, 276, 437,
+	91, 423, 424, 425, 384, 101, 96, 411, 412, 427,
+	101, 410, 382, 14, 386, 340, 323, 414, 192, 416,
+	417, 61, 272, 32, 65, 225, 225, 225, 225, 132,
+	408, 111, 67, 68, 69, 136, 372, 327, 139, 34,
+	35, 36, 37, 65, 64, 152, 269, 99, 114, 354,
+	292, 67, 68, 69, 290, 222, 95, 55, 116, 27,
+	115, 62, 58, 64, 13, 12, 160, 159, 167, 168,
+	162, 163, 164, 165, 166, 161, 61, 116, 199, 261,
+	76, 117, 118, 119, 11, 10, 120, 112, 113, 216,
+	9, 100, 217, 122, 226, 96, 160, 159, 167, 168,
+	162, 163, 164, 165, 166, 161, 8, 7, 6, 5,
+	4, 2, 105, 106, 94, 101, 0, 0, 107, 0,
+	108, 0, 0, 101, 0, 101, 101, 65, 0, 418,
+	419, 420, 114, 110, 0, 67, 68, 69, 96, 96,
+	0, 0, 65, 0, 115, 226, 0, 64, 0, 65,
+	67, 68, 69, 0, 0, 0, 0, 67, 68, 69,
+	0, 116, 64, 0, 76, 117, 118, 119, 0, 64,
+	120, 112, 113, 0, 0, 100, 226, 122, 0, 82,
+	0, 0, 0, 116, 0, 65, 76, 117, 118, 119,
+	0, 0, 120, 67, 68, 69, 105, 106, 94, 122,
+	0, 0, 107, 0, 108, 64, 0, 160, 159, 167,
+	168, 162, 163, 164, 165, 166, 161, 110, 105, 106,
+	96, 0, 82, 0, 107, 0, 108, 0, 0, 293,
+	296, 297, 298, 294, 338, 295, 299, 339, 0, 110,
+	226, 226, 226, 226, 0, 0, 14, 0, 293, 296,
+	297, 298, 294, 358, 295, 299, 359, 65, 390, 361,
+	363, 0, 114, 0, 0, 67, 68, 69, 0, 0,
+	0, 0, 0, 0, 115, 0, 0, 64, 160, 159,
+	167, 168, 162, 163, 164, 165, 166, 161, 0, 0,
+	0, 116, 0, 0, 76, 117, 118, 119, 0, 0,
+	120, 112, 113, 0, 0, 100, 0, 122, 0, 0,
+	0, 65, 0, 0, 0, 0, 114, 0, 0, 67,
+	68, 69, 14, 0, 0, 407, 105, 106, 115, 0,
+	96, 64, 107, 65, 108, 0, 14, 15, 16, 17,
+	0, 67, 68, 69, 0, 116, 0, 110, 76, 117,
+	118, 119, 0, 64, 120, 112, 113, 0, 0, 100,
+	406, 122, 18, 0, 0, 0, 0, 116, 0, 65,
+	76, 117, 118, 119, 0, 0, 120, 67, 68, 69,
+	105, 106, 0, 122, 0, 0, 107, 0, 108, 64,
+	160, 159, 167, 168, 162, 163, 164, 165, 166, 161,
+	0, 110, 105, 106, 0, 0, 76, 0, 107, 0,
+	108, 0, 0, 0, 0, 0, 0, 0, 0, 0,
+	0, 0, 0, 110, 0, 0, 0, 19, 20, 22,
+	21, 23, 0, 0, 0, 0, 0, 154, 157, 0,
+	24, 25, 26, 169, 170, 171, 172, 173, 174, 175,
+	158, 155, 156, 153, 160, 159, 167, 168, 162, 163,
+	164, 165, 166, 161, 317, 0, 0, 0, 0, 0,
+	0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
+	0, 0, 160, 159, 167, 168, 162, 163, 164, 165,
+	166, 161, 65, 0, 0, 65, 0, 0, 0, 0,
+	67, 68, 69, 67, 68, 69, 0, 0, 0, 0,
+	0, 0, 64, 0, 0, 64, 0, 0, 0, 0,
+	0, 0, 0, 0, 0, 0, 116, 0, 0, 62,
+	0, 0, 62,
 }
-var yyPact = [...]int{
-
-	556, -1000, -1000, 424, -1000, -1000, -1000, -1000, -1000, -1000,
-	-1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000, -53,
-	-54, -36, -46, -37, -1000, -1000, -1000, 417, 346, -1000,
-	-1000, -1000, 330, -1000, -64, 119, 404, 100, -68, -40,
-	96, -1000, -38, 96, -1000, 119, -76, 132, -76, 119,
-	-1000, -1000, -1000, -1000, -1000, 498, 96, -1000, 70, 343,
-	331, -10, -1000, 119, 149, -1000, 35, -1000, -12, -1000,
-	119, 57, 123, -1000, -1000, 119, -1000, -52, 119, 349,
-	285, 96, -1000, 245, -1000, -1000, 220, -24, 180, 714,
-	-1000, 660, 553, -1000, -1000, -1000, -19, -19, -19, -19,
-	258, 258, -1000, -1000, -1000, 258, 258, -1000, -1000, -1000,
-	-1000, -1000, -1000, -19, 368, -1000, 119, 100, 119, 402,
-	100, -19, 96, -1000, 348, -79, -1000, 89, -1000, 119,
-	-1000, -1000, 119, -1000, 182, 498, -1000, -1000, 96, 90,
-	660, 660, 201, -19, 140, 139, -19, -19, -19, 201,
-	-19, -19, -19, -19, -19, -19, -19, -19, -19, -19,
-	-1000, -1000, -1000, -1000, -1000, -1000, -1000, 12, 714, 144,
-	298, 260, 714, -1000, 677, -1000, -1000, 763, 449, 498,
-	-1000, 417, 234, 39, 773, 119, -1000, -1000, 283, 272,
-	-1000, 375, 660, -1000, 773, -1000, -1000, -1000, 275, 96,
-	-1000, -60, -1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000,
-	248, 367, -1000, -1000, 156, 352, 226, -25, -1000, -1000,
-	-1000, 12, 41, -1000, -1000, 152, -1000, -1000, 773, -1000,
-	677, -1000, -1000, 140, -19, -19, -19, 773, 773, 752,
-	-1000, 166, 26, -1000, 5, 5, -5, -5, -5, -9,
-	-9, -1000, -1000, -1000, -19, -1000, 773, -1000, -1000, 216,
-	498, 216, 251, 104, -1000, 660, -1000, 344, 100, 100,
-	375, 363, 369, 180, 119, -1000, -1000, 119, -1000, 389,
-	182, 182, 182, 182, -1000, 310, 297, -1000, 304, 296,
-	303, -15, -1000, 119, 119, -1000, 262, 119, -1000, -1000,
-	-1000, 260, -1000, 773, 773, 742, -19, 773, -1000, 216,
-	-1000, 234, -27, -1000, -19, 107, 271, 258, 424, 232,
-	195, -1000, 363, -1000, -19, -19, -1000, -1000, 385, 366,
-	367, 270, 412, -1000, -1000, -1000, -1000, 293, -1000, 282,
-	-1000, -1000, -1000, -41, -42, -49, -1000, -1000, -1000, -1000,
-	-1000, -19, 773, -1000, 137, -1000, 773, -19, -1000, 340,
-	243, -1000, -1000, -1000, 100, -1000, 48, 240, -1000, 141,
-	-1000, 375, 660, -19, 660, 660, -1000, -1000, 258, 258,
-	258, 773, -1000, 773, 334, 258, -1000, -19, -19, -1000,
-	-1000, -1000, 363, 180, 218, 180, 180, 96, 96, 96,
-	410, -1000, 773, -1000, 339, 142, -1000, 142, 142, 100,
-	-1000, 408, 6, -1000, 96, -1000, -1000, 149, -1000, 96,
-	-1000, 96, -1000,
+
+var yyPact = [...]int16{
+	807, -1000, -1000, 426, -1000, -1000, -1000, -1000, -1000, -1000,
+	-1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000, -58,
+	-55, -37, -43, -38, -1000, -1000, -1000, 427, 346, -1000,
+	-1000, -1000, 263, -1000, -69, 955, 409, 829, -73, -41,
+	645, -1000, -39, 645, -1000, 955, -77, 68, -77, 955,
+	-1000, -1000, -1000, -1000, -1000, 587, 645, -1000, 65, 306,
+	277, -23, -1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000,
+	955, 131, -1000, 12, -1000, -25, -1000, -1000, 955, 31,
+	36, -1000, -1000, -1000, 955, -1000, -53, 955, 339, 197,
+	645, -1000, 141, -1000, -1000, 359, -26, 96, 849, -1000,
+	771, 717, -1000, -1000, -1000, 609, 609, 609, 609, 97,
+	97, -1000, -1000, -1000, 97, 97, -1000, -1000, -1000, -1000,
+	-1000, -1000, 609, 385, -1000, 955, 829, 955, 400, 829,
+	609, 645, -1000, 329, -80, -1000, 63, -1000, 955, -1000,
+	-1000, 955, -1000, 952, 587, -1000, -1000, 645, 164, 771,
+	771, 202, 609, 79, 39, 609, 609, 609, 202, 609,
+	609, 609, 609, 609, 609, 609, 609, 609, 609, -1000,
+	-1000, -1000, -1000, -1000, -1000, -1000, -3, 849, 286, 271,
+	198, 849, -1000, 793, -1000, -1000, 602, 503, 587, -1000,
+	427, 312, 19, 25, 955, -1000, -1000, 221, 165, -1000,
+	378, 771, -1000, 25, -1000, -1000, -1000, 191, 645, -1000,
+	-65, -1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000, 143,
+	666, -1000, -1000, 333, 332, 484, -28, -1000, -1000, -1000,
+	-3, 57, -1000, -1000, 125, -1000, -1000, 25, -1000, 793,
+	-1000, -1000, 79, 609, 609, 609, 25, 25, 877, -1000,
+	80, 381, -1000, -10, -10, -18, -18, -18, 13, 13,
+	-1000, -1000, -1000, 609, -1000, 25, -1000, -1000, 168, 587,
+	168, 170, 16, -1000, 771, -1000, 364, 829, 829, 378,
+	388, 368, 96, 955, -1000, -1000, 955, -1000, 386, 952,
+	952, 952, 952, -1000, 242, 230, -1000, 285, 253, 331,
+	-17, -1000, 955, -1000, -1000, 955, -1000, 213, 955, -1000,
+	-1000, -1000, 198, -1000, 25, 25, 673, 609, 25, -1000,
+	168, -1000, 312, -30, -1000, 609, 180, 176, 97, 426,
+	88, 146, -1000, 388, 262, 609, 609, 609, -1000, -1000,
+	393, 362, 666, 82, 685, -1000, -1000, -1000, -1000, 229,
+	-1000, 190, -1000, -1000, -1000, -46, -47, -50, -1000, -1000,
+	-1000, -1000, -1000, 609, 25, -1000, 81, -1000, 25, 609,
+	262, 304, 163, -1000, 262, -1000, 829, 262, -1000, 587,
+	785, 491, 161, -1000, 461, -1000, 378, 771, 609, 771,
+	771, -1000, -1000, 97, 97, 97, 25, -1000, 25, -1000,
+	296, 97, -1000, -1000, -1000, 153, 609, 609, 609, 609,
+	321, -1000, -1000, 388, 96, 148, 96, 96, 645, 645,
+	645, 418, -1000, 25, 25, 25, -1000, -1000, 316, 319,
+	104, -1000, 104, 104, 829, -1000, -1000, -1000, 417, -7,
+	-1000, 645, -1000, -1000, 131, -1000, 645, -1000, 645, -1000,
 }
-var yyPgo = [...]int{
-
-	0, 521, 17, 520, 519, 513, 511, 506, 497, 496,
-	495, 494, 493, 499, 491, 490, 488, 22, 27, 487,
-	15, 16, 12, 486, 485, 11, 483, 21, 26, 481,
-	3, 25, 72, 480, 479, 476, 14, 2, 23, 13,
-	9, 475, 7, 74, 4, 474, 472, 20, 470, 468,
-	467, 466, 5, 465, 6, 464, 1, 462, 458, 455,
-	10, 8, 24, 446, 361, 315, 444, 443, 442, 441,
-	440, 0, 439, 413, 438, 436, 70, 435, 434, 244,
-	19,
+
+var yyPgo = [...]int16{
+	0, 591, 23, 590, 589, 588, 587, 586, 570, 565,
+	564, 545, 544, 503, 542, 539, 537, 11, 7, 16,
+	536, 22, 18, 14, 535, 534, 13, 530, 17, 30,
+	529, 6, 29, 109, 527, 525, 517, 25, 74, 19,
+	21, 5, 516, 3, 31, 20, 511, 502, 15, 498,
+	496, 495, 494, 9, 492, 8, 491, 489, 1, 479,
+	478, 477, 10, 2, 28, 465, 315, 294, 464, 462,
+	461, 456, 452, 4, 450, 0, 27, 448, 430, 445,
+	443, 12, 440, 435, 215, 26,
 }
-var yyR1 = [...]int{
 
-	0, 77, 1, 1, 1, 1, 1, 1, 1, 1,
+var yyR1 = [...]int8{
+	0, 82, 1, 1, 1, 1, 1, 1, 1, 1,
 	1, 1, 1, 2, 2, 2, 3, 3, 4, 5,
 	6, 7, 7, 7, 8, 8, 8, 9, 10, 10,
-	10, 11, 12, 12, 12, 78, 13, 14, 14, 15,
-	15, 15, 15, 15, 16, 16, 17, 17, 18, 18,
-	18, 19, 19, 72, 72, 72, 20, 20, 21, 21,
-	22, 22, 22, 23, 23, 23, 23, 75, 75, 74,
-	74, 74, 24, 24, 24, 24, 25, 25, 25, 25,
-	26, 26, 27, 27, 28, 28, 29, 29, 29, 29,
-	30, 30, 31, 31, 32, 32, 32, 32, 32, 32,
-	33, 33, 33, 33, 33, 33, 33, 33, 33, 33,
-	33, 33, 33, 33, 38, 38, 38, 38, 38, 38,
-	34, 34, 34, 34, 34, 34, 34, 39, 39, 39,
-	43, 40, 40, 37, 37, 37, 37, 37, 37, 37,
-	37, 37, 37, 37, 37, 37, 37, 37, 37, 37,
-	37, 37, 37, 37, 37, 45, 48, 48, 46, 46,
-	47, 49, 49, 44, 44, 36, 36, 36, 36, 50,
-	50, 51, 51, 52, 52, 53, 53, 54, 55, 55,
-	55, 56, 56, 56, 57, 57, 57, 58, 58, 59,
-	59, 60, 60, 35, 35, 41, 41, 42, 42, 61,
-	61, 62, 63, 63, 65, 65, 66, 66, 64, 64,
-	67, 67, 67, 67, 67, 68, 68, 69, 69, 70,
-	70, 71, 73, 79, 80, 76,
+	10, 11, 12, 12, 12, 83, 13, 14, 14, 15,
+	15, 15, 15, 15, 16, 16, 17, 17, 19, 19,
+	19, 20, 20, 74, 74, 74, 21, 21, 22, 22,
+	23, 23, 23, 24, 24, 24, 24, 80, 80, 79,
+	79, 79, 25, 25, 25, 25, 26, 26, 26, 26,
+	27, 27, 28, 28, 29, 29, 30, 30, 30, 30,
+	31, 31, 32, 32, 33, 33, 33, 33, 33, 33,
+	34, 34, 34, 34, 34, 34, 34, 34, 34, 34,
+	34, 34, 34, 34, 39, 39, 39, 39, 39, 39,
+	35, 35, 35, 35, 35, 35, 35, 40, 40, 40,
+	44, 41, 41, 38, 38, 38, 38, 38, 38, 38,
+	38, 38, 38, 38, 38, 38, 38, 38, 38, 38,
+	38, 38, 38, 38, 38, 46, 49, 49, 47, 47,
+	48, 50, 50, 45, 45, 37, 37, 37, 37, 51,
+	51, 52, 52, 53, 53, 54, 54, 55, 56, 56,
+	56, 57, 57, 57, 58, 58, 58, 58, 58, 58,
+	59, 59, 59, 60, 60, 61, 61, 62, 62, 18,
+	18, 36, 36, 42, 42, 43, 43, 63, 63, 64,
+	65, 65, 67, 67, 68, 68, 66, 66, 69, 69,
+	69, 69, 69, 70, 70, 71, 71, 72, 72, 73,
+	73, 75, 75, 75, 76, 76, 76, 78, 78, 77,
+	77, 84, 85, 81,
 }
-var yyR2 = [...]int{
 
+var yyR2 = [...]int8{
 	0, 1, 1, 1, 1, 1, 1, 1, 1, 1,
-	1, 1, 1, 12, 6, 3, 8, 8, 8, 7,
+	1, 1, 1, 12, 6, 3, 9, 9, 9, 8,
 	3, 5, 8, 4, 6, 7, 4, 5, 4, 5,
 	5, 3, 2, 2, 2, 0, 2, 0, 2, 1,
 	2, 1, 1, 1, 0, 1, 1, 3, 1, 2,
@@ -491,135 +522,142 @@ var yyR2 = [...]int{
 	3, 3, 3, 3, 3, 3, 2, 2, 2, 3,
 	3, 4, 5, 4, 1, 5, 0, 1, 1, 2,
 	4, 0, 2, 1, 3, 1, 1, 1, 1, 0,
-	3, 0, 2, 0, 3, 1, 3, 2, 0, 1,
-	1, 0, 2, 4, 0, 2, 4, 0, 3, 1,
-	3, 0, 5, 2, 1, 1, 3, 3, 1, 1,
-	3, 3, 1, 1, 0, 2, 0, 3, 0, 1,
-	1, 1, 1, 1, 1, 0, 1, 0, 1, 0,
-	2, 1, 1, 1, 1, 0,
+	3, 0, 2, 0, 3, 1, 3, 3, 0, 1,
+	1, 0, 2, 2, 0, 2, 4, 4, 2, 4,
+	0, 2, 4, 0, 3, 1, 3, 0, 5, 0,
+	2, 2, 1, 1, 3, 3, 1, 1, 3, 3,
+	1, 1, 0, 2, 0, 3, 0, 1, 1, 1,
+	1, 1, 1, 0, 1, 0, 1, 0, 2, 1,
+	1, 1, 1, 1, 1, 1, 1, 1, 1, 1,
+	1, 1, 1, 0,
 }
-var yyChk = [...]int{
-
-	-1000, -77, -1, -2, -3, -4, -5, -6, -7, -8,
-	-9, -10, -11, -12, 9, 10, 11, 12, 31, 95,
-	96, 98, 97, 99, 108, 109, 110, -15, 5, 6,
-	7, 8, -13, -78, -13, -13, -13, -13, 100, -69,
-	102, 106, -64, 102, 104, 100, 100, 101, 102, 100,
-	-76, -76, -76, -2, 21, -16, 36, 22, -14, -64,
-	-28, -73, 52, 13, -61, -62, -44, -71, -73, 52,
-	-66, 105, 101, -71, 52, 100, -71, -73, -65, 105,
-	52, -65, -73, -17, -18, 86, -19, -73, -32, -37,
-	-33, 63, -79, -36, -44, -42, 84, 85, 90, 92,
-	-71, 105, -45, 59, 60, 24, 33, 49, 53, 54,
-	55, 58, -43, 65, -71, 57, 27, 31, 93, -28,
-	50, 69, 93, -73, 63, 52, -76, -73, -76, 103,
-	-73, 24, 48, -71, 13, 50, -72, -71, 23, 93,
-	62, 61, 76, -34, 79, 63, 77, 78, 64, 76,
-	81, 80, 89, 84, 85, 86, 87, 88, 82, 83,
-	69, 70, 71, 72, 73, 74, 75, -32, -37, -32,
-	-2, -40, -37, -37, -79, -37, -37, -37, -79, -79,
-	-43, -79, -79, -48, -37, -63, 20, 13, -28, -61,
-	-73, -31, 14, -62, -37, -71, -76, 24, -70, 107,
-	-67, 98, 96, 30, 97, 17, 52, -73, -73, -76,
-	-20, -21, -22, -23, -27, -43, -79, -73, -18, -71,
-	86, -32, -32, -38, 58, 63, 59, 60, -37, -39,
-	-79, -43, 56, 79, 77, 78, 64, -37, -37, -37,
-	-38, -37, -37, -37, -37, -37, -37, -37, -37, -37,
-	-37, -80, 51, -80, 50, -80, -37, -71, -80, -17,
-	22, -17, -36, -46, -47, 66, -27, -58, 31, -79,
-	-31, -52, 17, -32, 48, -71, -76, -68, 103, -31,
-	50, -24, -25, -26, 38, 42, 44, 39, 40, 41,
-	45, -74, -73, 23, -75, 23, -20, 93, 58, 59,
-	60, -40, -39, -37, -37, -37, 62, -37, -80, -17,
-	-80, 50, -49, -47, 68, -32, -35, 34, -2, -61,
-	-59, -44, -52, -56, 19, 18, -73, -73, -50, 15,
-	-21, -22, -21, -22, 38, 38, 38, 43, 38, 43,
-	38, -25, -29, 46, 104, 47, -73, -73, -80, -73,
-	-80, 62, -37, -80, -36, 94, -37, 67, -60, 48,
-	-41, -42, -60, -80, 50, -56, -37, -53, -54, -37,
-	-76, -51, 16, 18, 48, 48, 38, 38, 101, 101,
-	101, -37, -80, -37, 28, 50, -44, 50, 50, -55,
-	25, 26, -52, -32, -40, -32, -32, -79, -79, -79,
-	29, -42, -37, -54, -56, -30, -71, -30, -30, 11,
-	-57, 20, 32, -80, 50, -80, -80, -61, 11, 79,
-	-71, -71, -71,
+
+var yyChk = [...]int16{
+	-1000, -82, -1, -2, -3, -4, -5, -6, -7, -8,
+	-9, -10, -11, -12, 9, 10, 11, 12, 35, 100,
+	101, 103, 102, 104, 113, 114, 115, -15, 5, 6,
+	7, 8, -13, -83, -13, -13, -13, -13, 105, -71,
+	107, 111, -66, 107, 109, 105, 105, 106, 107, 105,
+	-81, -81, -81, -2, 22, -16, 41, 23, -14, -66,
+	-29, -78, 57, -75, 40, 20, -76, 28, 29, 30,
+	13, -63, -64, -45, -73, -78, 57, -75, -68, 110,
+	106, -73, 57, -75, 105, -73, -78, -67, 110, 57,
+	-67, -78, -17, -19, 91, -20, -78, -33, -38, -34,
+	68, -84, -37, -45, -43, 89, 90, 95, 97, -73,
+	110, -46, 64, 65, 25, 37, 54, 58, 59, 60,
+	63, -44, 70, -73, 62, 31, 35, 98, -29, 55,
+	74, 98, -78, 68, 57, -81, -78, -81, 108, -78,
+	25, 53, -73, 13, 55, -74, -73, 24, 98, 67,
+	66, 81, -35, 84, 68, 82, 83, 69, 81, 86,
+	85, 94, 89, 90, 91, 92, 93, 87, 88, 74,
+	75, 76, 77, 78, 79, 80, -33, -38, -33, -2,
+	-41, -38, -38, -84, -38, -38, -38, -84, -84, -44,
+	-84, -84, -49, -38, -65, 21, 13, -29, -63, -78,
+	-32, 14, -64, -38, -73, -81, 25, -72, 112, -69,
+	103, 101, 34, 102, 17, 57, -78, -78, -81, -21,
+	-22, -23, -24, -28, -44, -84, -78, -19, -73, 91,
+	-33, -33, -39, 63, 68, 64, 65, -38, -40, -84,
+	-44, 61, 84, 82, 83, 69, -38, -38, -38, -39,
+	-38, -38, -38, -38, -38, -38, -38, -38, -38, -38,
+	-85, 56, -85, 55, -85, -38, -73, -85, -17, 23,
+	-17, -37, -47, -48, 71, -28, -60, 35, -84, -32,
+	-53, 17, -33, 53, -73, -81, -70, 108, -32, 55,
+	-25, -26, -27, 43, 47, 49, 44, 45, 46, 50,
+	-79, -77, 24, 57, -76, -80, 24, -21, 98, 63,
+	64, 65, -41, -40, -38, -38, -38, 67, -38, -85,
+	-17, -85, 55, -50, -48, 73, -33, -36, 38, -2,
+	-63, -61, -45, -53, -58, 19, 20, 18, -78, -78,
+	-51, 15, -22, -23, -22, -23, 43, 43, 43, 48,
+	43, 48, 43, -26, -30, 51, 109, 52, -78, -78,
+	-85, -78, -85, 67, -38, -85, -37, 99, -38, 72,
+	-62, 53, -42, -43, -62, -85, 55, -58, -18, 40,
+	-38, -38, -54, -55, -38, -81, -52, 16, 18, 53,
+	53, 43, 43, 106, 106, 106, -38, -85, -38, -18,
+	32, 55, -18, -45, -18, -17, 55, 20, 19, 55,
+	-56, 26, 27, -53, -33, -41, -33, -33, -84, -84,
+	-84, 33, -43, -38, -38, -38, -55, -57, 28, -58,
+	-31, -73, -31, -31, 11, 29, 30, -59, 21, 36,
+	-85, 55, -85, -85, -63, 11, 84, -73, -73, -73,
 }
-var yyDef = [...]int{
 
+var yyDef = [...]int16{
 	0, -2, 1, 2, 3, 4, 5, 6, 7, 8,
-	9, 10, 11, 12, 35, 35, 35, 35, 35, 217,
-	208, 0, 0, 0, 225, 225, 225, 0, 39, 41,
-	42, 43, 44, 37, 208, 0, 0, 0, 206, 0,
-	0, 218, 0, 0, 209, 0, 204, 0, 204, 0,
+	9, 10, 11, 12, 35, 35, 35, 35, 35, 225,
+	216, 0, 0, 0, 243, 243, 243, 0, 39, 41,
+	42, 43, 44, 37, 216, 0, 0, 0, 214, 0,
+	0, 226, 0, 0, 217, 0, 212, 0, 212, 0,
 	32, 33, 34, 15, 40, 0, 0, 45, 36, 0,
-	0, 84, 222, 0, 20, 199, 0, 163, 0, -2,
-	0, 0, 0, 225, 221, 0, 225, 0, 0, 0,
-	0, 0, 31, 0, 46, 48, 53, 0, 51, 52,
-	94, 0, 0, 133, 134, 135, 0, 0, 0, 0,
-	163, 0, 154, 100, 101, 0, 0, 223, 165, 166,
-	167, 168, 198, 156, 0, 38, 0, 0, 0, 92,
-	0, 0, 0, 225, 0, 219, 23, 0, 26, 0,
-	28, 205, 0, 225, 0, 0, 49, 54, 0, 0,
+	0, 84, 237, 238, 231, 232, 233, 234, 235, 236,
+	0, 20, 207, 0, 163, 0, -2, -2, 0, 0,
+	0, 243, 229, 230, 0, 243, 0, 0, 0, 0,
+	0, 31, 0, 46, 48, 53, 0, 51, 52, 94,
+	0, 0, 133, 134, 135, 0, 0, 0, 0, 163,
+	0, 154, 100, 101, 0, 0, 241, 165, 166, 167,
+	168, 206, 156, 0, 38, 0, 0, 0, 92, 0,
+	0, 0, 243, 0, 227, 23, 0, 26, 0, 28,
+	213, 0, 243, 0, 0, 49, 54, 0, 0, 0,
 	0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
-	0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
-	120, 121, 122, 123, 124, 125, 126, 97, 0, 0,
-	0, 0, 131, 146, 0, 147, 148, 0, 0, 0,
-	112, 0, 0, 0, 157, 0, 202, 203, 187, 92,
-	85, 173, 0, 200, 201, 164, 21, 207, 0, 0,
-	225, 215, 210, 211, 212, 213, 214, 27, 29, 30,
-	92, 56, 58, 59, 69, 67, 0, 82, 47, 55,
-	50, 95, 96, 99, 114, 0, 116, 118, 102, 103,
-	0, 128, 129, 0, 0, 0, 0, 105, 107, 0,
-	111, 136, 137, 138, 139, 140, 141, 142, 143, 144,
-	145, 98, 224, 130, 0, 197, 131, 149, 150, 0,
-	0, 0, 0, 161, 158, 0, 14, 0, 0, 0,
-	173, 181, 0, 93, 0, 220, 24, 0, 216, 169,
-	0, 0, 0, 0, 72, 0, 0, 75, 0, 0,
-	0, 86, 70, 0, 0, 68, 0, 0, 115, 117,
-	119, 0, 104, 106, 108, 0, 0, 132, 151, 0,
-	153, 0, 0, 159, 0, 0, 191, 0, 194, 191,
-	0, 189, 181, 19, 0, 0, 225, 25, 171, 0,
-	57, 63, 0, 66, 73, 74, 76, 0, 78, 0,
-	80, 81, 60, 0, 0, 0, 71, 61, 62, 83,
-	127, 0, 109, 152, 0, 155, 162, 0, 16, 0,
-	193, 195, 17, 188, 0, 18, 182, 174, 175, 178,
-	22, 173, 0, 0, 0, 0, 77, 79, 0, 0,
-	0, 110, 113, 160, 0, 0, 190, 0, 0, 177,
-	179, 180, 181, 172, 170, 64, 65, 0, 0, 0,
-	0, 196, 183, 176, 184, 0, 90, 0, 0, 0,
-	13, 0, 0, 87, 0, 88, 89, 192, 185, 0,
-	91, 0, 186,
+	0, 0, 0, 0, 0, 0, 0, 0, 0, 120,
+	121, 122, 123, 124, 125, 126, 97, 0, 0, 0,
+	0, 131, 146, 0, 147, 148, 0, 0, 0, 112,
+	0, 0, 0, 157, 0, 210, 211, 193, 92, 85,
+	173, 0, 208, 209, 164, 21, 215, 0, 0, 243,
+	223, 218, 219, 220, 221, 222, 27, 29, 30, 92,
+	56, 58, 59, 69, 67, 0, 82, 47, 55, 50,
+	95, 96, 99, 114, 0, 116, 118, 102, 103, 0,
+	128, 129, 0, 0, 0, 0, 105, 107, 0, 111,
+	136, 137, 138, 139, 140, 141, 142, 143, 144, 145,
+	98, 242, 130, 0, 205, 131, 149, 150, 0, 0,
+	0, 0, 161, 158, 0, 14, 0, 0, 0, 173,
+	184, 0, 93, 0, 228, 24, 0, 224, 169, 0,
+	0, 0, 0, 72, 0, 0, 75, 0, 0, 0,
+	86, 70, 0, 239, 240, 0, 68, 0, 0, 115,
+	117, 119, 0, 104, 106, 108, 0, 0, 132, 151,
+	0, 153, 0, 0, 159, 0, 0, 197, 0, 202,
+	197, 0, 195, 184, 199, 0, 0, 0, 243, 25,
+	171, 0, 57, 63, 0, 66, 73, 74, 76, 0,
+	78, 0, 80, 81, 60, 0, 0, 0, 71, 61,
+	62, 83, 127, 0, 109, 152, 0, 155, 162, 0,
+	199, 0, 201, 203, 199, 194, 0, 199, 19, 0,
+	185, 188, 174, 175, 178, 22, 173, 0, 0, 0,
+	0, 77, 79, 0, 0, 0, 110, 113, 160, 16,
+	0, 0, 17, 196, 18, 200, 0, 0, 0, 0,
+	181, 179, 180, 184, 172, 170, 64, 65, 0, 0,
+	0, 0, 204, 186, 187, 189, 176, 177, 0, 190,
+	0, 90, 0, 0, 0, 182, 183, 13, 0, 0,
+	87, 0, 88, 89, 198, 191, 0, 91, 0, 192,
 }
-var yyTok1 = [...]int{
 
+var yyTok1 = [...]int8{
 	1, 3, 3, 3, 3, 3, 3, 3, 3, 3,
 	3, 3, 3, 3, 3, 3, 3, 3, 3, 3,
 	3, 3, 3, 3, 3, 3, 3, 3, 3, 3,
-	3, 3, 3, 3, 3, 3, 3, 88, 81, 3,
-	49, 51, 86, 84, 50, 85, 93, 87, 3, 3,
+	3, 3, 3, 3, 3, 3, 3, 93, 86, 3,
+	54, 56, 91, 89, 55, 90, 98, 92, 3, 3,
 	3, 3, 3, 3, 3, 3, 3, 3, 3, 3,
-	70, 69, 71, 3, 3, 3, 3, 3, 3, 3,
+	75, 74, 76, 3, 3, 3, 3, 3, 3, 3,
 	3, 3, 3, 3, 3, 3, 3, 3, 3, 3,
 	3, 3, 3, 3, 3, 3, 3, 3, 3, 3,
-	3, 3, 3, 3, 89, 3, 3, 3, 3, 3,
+	3, 3, 3, 3, 94, 3, 3, 3, 3, 3,
 	3, 3, 3, 3, 3, 3, 3, 3, 3, 3,
 	3, 3, 3, 3, 3, 3, 3, 3, 3, 3,
-	3, 3, 3, 3, 80, 3, 90,
+	3, 3, 3, 3, 85, 3, 95,
 }
-var yyTok2 = [...]int{
 
+var yyTok2 = [...]int8{
 	2, 3, 4, 5, 6, 7, 8, 9, 10, 11,
 	12, 13, 14, 15, 16, 17, 18, 19, 20, 21,
 	22, 23, 24, 25, 26, 27, 28, 29, 30, 31,
 	32, 33, 34, 35, 36, 37, 38, 39, 40, 41,
-	42, 43, 44, 45, 46, 47, 48, 52, 53, 54,
-	55, 56, 57, 58, 59, 60, 61, 62, 63, 64,
-	65, 66, 67, 68, 72, 73, 74, 75, 76, 77,
-	78, 79, 82, 83, 91, 92, 94, 95, 96, 97,
-	98, 99, 100, 101, 102, 103, 104, 105, 106, 107,
-	108, 109, 110,
+	42, 43, 44, 45, 46, 47, 48, 49, 50, 51,
+	52, 53, 57, 58, 59, 60, 61, 62, 63, 64,
+	65, 66, 67, 68, 69, 70, 71, 72, 73, 77,
+	78, 79, 80, 81, 82, 83, 84, 87, 88, 96,
+	97, 99, 100, 101, 102, 103, 104, 105, 106, 107,
+	108, 109, 110, 111, 112, 113, 114, 115,
 }
-var yyTok3 = [...]int{
+
+var yyTok3 = [...]int8{
 	0,
 }
 
@@ -701,9 +739,9 @@ func yyErrorMessage(state, lookAhead int) string {
 	expected := make([]int, 0, 4)
 
 	// Look for shiftable tokens.
-	base := yyPact[state]
+	base := int(yyPact[state])
 	for tok := TOKSTART; tok-1 < len(yyToknames); tok++ {
-		if n := base + tok; n >= 0 && n < yyLast && yyChk[yyAct[n]] == tok {
+		if n := base + tok; n >= 0 && n < yyLast && int(yyChk[int(yyAct[n])]) == tok {
 			if len(expected) == cap(expected) {
 				return res
 			}
@@ -713,13 +751,13 @@ func yyErrorMessage(state, lookAhead int) string {
 
 	if yyDef[state] == -2 {
 		i := 0
-		for yyExca[i] != -1 || yyExca[i+1] != state {
+		for yyExca[i] != -1 || int(yyExca[i+1]) != state {
 			i += 2
 		}
 
 		// Look for tokens that we accept or reduce.
 		for i += 2; yyExca[i] >= 0; i += 2 {
-			tok := yyExca[i]
+			tok := int(yyExca[i])
 			if tok < TOKSTART || yyExca[i+1] == 0 {
 				continue
 			}
@@ -750,30 +788,30 @@ func yylex1(lex yyLexer, lval *yySymType) (char, token int) {
 	token = 0
 	char = lex.Lex(lval)
 	if char <= 0 {
-		token = yyTok1[0]
+		token = int(yyTok1[0])
 		goto out
 	}
 	if char < len(yyTok1) {
-		token = yyTok1[char]
+		token = int(yyTok1[char])
 		goto out
 	}
 	if char >= yyPrivate {
 		if char < yyPrivate+len(yyTok2) {
-			token = yyTok2[char-yyPrivate]
+			token = int(yyTok2[char-yyPrivate])
 			goto out
 		}
 	}
 	for i := 0; i < len(yyTok3); i += 2 {
-		token = yyTok3[i+0]
+		token = int(yyTok3[i+0])
 		if token == char {
-			token = yyTok3[i+1]
+			token = int(yyTok3[i+1])
 			goto out
 		}
 	}
 
 out:
 	if token == 0 {
-		token = yyTok2[1] /* unknown char */
+		token = int(yyTok2[1]) /* unknown char */
 	}
 	if yyDebug >= 3 {
 		__yyfmt__.Printf("lex %s(%d)\n", yyTokname(token), uint(char))
@@ -828,7 +866,7 @@ yystack:
 	yyS[yyp].yys = yystate
 
 yynewstate:
-	yyn = yyPact[yystate]
+	yyn = int(yyPact[yystate])
 	if yyn <= yyFlag {
 		goto yydefault /* simple state */
 	}
@@ -839,8 +877,8 @@ yynewstate:
 	if yyn < 0 || yyn >= yyLast {
 		goto yydefault
 	}
-	yyn = yyAct[yyn]
-	if yyChk[yyn] == yytoken { /* valid shift */
+	yyn = int(yyAct[yyn])
+	if int(yyChk[yyn]) == yytoken { /* valid shift */
 		yyrcvr.char = -1
 		yytoken = -1
 		yyVAL = yyrcvr.lval
@@ -853,7 +891,7 @@ yynewstate:
 
 yydefault:
 	/* default state action */
-	yyn = yyDef[yystate]
+	yyn = int(yyDef[yystate])
 	if yyn == -2 {
 		if yyrcvr.char < 0 {
 			yyrcvr.char, yytoken = yylex1(yylex, &yyrcvr.lval)
@@ -862,18 +900,18 @@ yydefault:
 		/* look through exception table */
 		xi := 0
 		for {
-			if yyExca[xi+0] == -1 && yyExca[xi+1] == yystate {
+			if yyExca[xi+0] == -1 && int(yyExca[xi+1]) == yystate {
 				break
 			}
 			xi += 2
 		}
 		for xi += 2; ; xi += 2 {
-			yyn = yyExca[xi+0]
+			yyn = int(yyExca[xi+0])
 			if yyn < 0 || yyn == yytoken {
 				break
 			}
 		}
-		yyn = yyExca[xi+1]
+		yyn = int(yyExca[xi+1])
 		if yyn < 0 {
 			goto ret0
 		}
@@ -895,10 +933,10 @@ yydefault:
 
 			/* find a state where "error" is a legal shift action */
 			for yyp >= 0 {
-				yyn = yyPact[yyS[yyp].yys] + yyErrCode
+				yyn = int(yyPact[yyS[yyp].yys]) + yyErrCode
 				if yyn >= 0 && yyn < yyLast {
-					yystate = yyAct[yyn] /* simulate a shift of "error" */
-					if yyChk[yystate] == yyErrCode {
+					yystate = int(yyAct[yyn]) /* simulate a shift of "error" */
+					if int(yyChk[yystate]) == yyErrCode {
 						goto yystack
 					}
 				}
@@ -934,7 +972,7 @@ yydefault:
 	yypt := yyp
 	_ = yypt // guard against "declared and not used"
 
-	yyp -= yyR2[yyn]
+	yyp -= int(yyR2[yyn])
 	// yyp is now the index of $0. Perform the default action. Iff the
 	// reduced production is ε, $1 is possibly out of range.
 	if yyp+1 >= len(yyS) {
@@ -945,16 +983,16 @@ yydefault:
 	yyVAL = yyS[yyp+1]
 
 	/* consult goto table to find next state */
-	yyn = yyR1[yyn]
-	yyg := yyPgo[yyn]
+	yyn = int(yyR1[yyn])
+	yyg := int(yyPgo[yyn])
 	yyj := yyg + yyS[yyp].yys + 1
 
 	if yyj >= yyLast {
-		yystate = yyAct[yyg]
+		yystate = int(yyAct[yyg])
 	} else {
-		yystate = yyAct[yyj]
-		if yyChk[yystate] != -yyn {
-			yystate = yyAct[yyg]
+		yystate = int(yyAct[yyj])
+		if int(yyChk[yystate]) != -yyn {
+			yystate = int(yyAct[yyg])
 		}
 	}
 	// dummy call; replaced with literal code
@@ -962,25 +1000,25 @@ yydefault:
 
 	case 1:
 		yyDollar = yyS[yypt-1 : yypt+1]
-		//line sql.y:170
+//line sql.y:171
 		{
 			setParseTree(yylex, yyDollar[1].statement)
 		}
 	case 2:
 		yyDollar = yyS[yypt-1 : yypt+1]
-		//line sql.y:176
+//line sql.y:177
 		{
 			yyVAL.statement = yyDollar[1].selStmt
 		}
 	case 13:
 		yyDollar = yyS[yypt-12 : yypt+1]
-		//line sql.y:192
+//line sql.y:193
 		{
 			yyVAL.selStmt = &Select{Comments: Comments(yyDollar[2].bytes2), Distinct: yyDollar[3].str, SelectExprs: yyDollar[4].selectExprs, From: yyDollar[6].tableExprs, Where: NewWhere(WhereStr, yyDollar[7].boolExpr), GroupBy: GroupBy(yyDollar[8].valExprs), Having: NewWhere(HavingStr, yyDollar[9].boolExpr), OrderBy: yyDollar[10].orderBy, Limit: yyDollar[11].limit, Lock: yyDollar[12].str}
 		}
 	case 14:
 		yyDollar = yyS[yypt-6 : yypt+1]
-		//line sql.y:196
+//line sql.y:197
 		{
 			if yyDollar[4].sqlID != "value" {
 				yylex.Error("expecting value after next")
@@ -990,19 +1028,19 @@ yydefault:
 		}
 	case 15:
 		yyDollar = yyS[yypt-3 : yypt+1]
-		//line sql.y:204
+//line sql.y:205
 		{
 			yyVAL.selStmt = &Union{Type: yyDollar[2].str, Left: yyDollar[1].selStmt, Right: yyDollar[3].selStmt}
 		}
 	case 16:
-		yyDollar = yyS[yypt-8 : yypt+1]
-		//line sql.y:210
+		yyDollar = yyS[yypt-9 : yypt+1]
+//line sql.y:211
 		{
-			yyVAL.statement = &Insert{Comments: Comments(yyDollar[2].bytes2), Ignore: yyDollar[3].str, Table: yyDollar[5].tableName, Columns: yyDollar[6].columns, Rows: yyDollar[7].insRows, OnDup: OnDup(yyDollar[8].updateExprs)}
+			yyVAL.statement = &Insert{Comments: Comments(yyDollar[2].bytes2), Ignore: yyDollar[3].str, Table: yyDollar[5].tableName, Columns: yyDollar[6].columns, Rows: yyDollar[7].insRows, OnDup: OnDup(yyDollar[8].updateExprs), Returning: Returning(yyDollar[9].selectExprs)}
 		}
 	case 17:
-		yyDollar = yyS[yypt-8 : yypt+1]
-		//line sql.y:214
+		yyDollar = yyS[yypt-9 : yypt+1]
+//line sql.y:215
 		{
 			cols := make(Columns, 0, len(yyDollar[7].updateExprs))
 			vals := make(ValTuple, 0, len(yyDollar[7].updateExprs))
@@ -1010,381 +1048,381 @@ yydefault:
 				cols = append(cols, &NonStarExpr{Expr: col.Name})
 				vals = append(vals, col.Expr)
 			}
-			yyVAL.statement = &Insert{Comments: Comments(yyDollar[2].bytes2), Ignore: yyDollar[3].str, Table: yyDollar[5].tableName, Columns: cols, Rows: Values{vals}, OnDup: OnDup(yyDollar[8].updateExprs)}
+			yyVAL.statement = &Insert{Comments: Comments(yyDollar[2].bytes2), Ignore: yyDollar[3].str, Table: yyDollar[5].tableName, Columns: cols, Rows: Values{vals}, OnDup: OnDup(yyDollar[8].updateExprs), Returning: Returning(yyDollar[9].selectExprs)}
 		}
 	case 18:
-		yyDollar = yyS[yypt-8 : yypt+1]
-		//line sql.y:226
+		yyDollar = yyS[yypt-9 : yypt+1]
+//line sql.y:227
 		{
-			yyVAL.statement = &Update{Comments: Comments(yyDollar[2].bytes2), Table: yyDollar[3].tableName, Exprs: yyDollar[5].updateExprs, Where: NewWhere(WhereStr, yyDollar[6].boolExpr), OrderBy: yyDollar[7].orderBy, Limit: yyDollar[8].limit}
+			yyVAL.statement = &Update{Comments: Comments(yyDollar[2].bytes2), Table: yyDollar[3].tableName, Exprs: yyDollar[5].updateExprs, Where: NewWhere(WhereStr, yyDollar[6].boolExpr), OrderBy: yyDollar[7].orderBy, Limit: yyDollar[8].limit, Returning: Returning(yyDollar[9].selectExprs)}
 		}
 	case 19:
-		yyDollar = yyS[yypt-7 : yypt+1]
-		//line sql.y:232
+		yyDollar = yyS[yypt-8 : yypt+1]
+//line sql.y:233
 		{
-			yyVAL.statement = &Delete{Comments: Comments(yyDollar[2].bytes2), Table: yyDollar[4].tableName, Where: NewWhere(WhereStr, yyDollar[5].boolExpr), OrderBy: yyDollar[6].orderBy, Limit: yyDollar[7].limit}
+			yyVAL.statement = &Delete{Comments: Comments(yyDollar[2].bytes2), Table: yyDollar[4].tableName, Where: NewWhere(WhereStr, yyDollar[5].boolExpr), OrderBy: yyDollar[6].orderBy, Limit: yyDollar[7].limit, Returning: Returning(yyDollar[8].selectExprs)}
 		}
 	case 20:
 		yyDollar = yyS[yypt-3 : yypt+1]
-		//line sql.y:238
+//line sql.y:239
 		{
 			yyVAL.statement = &Set{Comments: Comments(yyDollar[2].bytes2), Exprs: yyDollar[3].updateExprs}
 		}
 	case 21:
 		yyDollar = yyS[yypt-5 : yypt+1]
-		//line sql.y:244
+//line sql.y:245
 		{
 			yyVAL.statement = &DDL{Action: CreateStr, NewName: yyDollar[4].sqlID}
 		}
 	case 22:
 		yyDollar = yyS[yypt-8 : yypt+1]
-		//line sql.y:248
+//line sql.y:249
 		{
 			// Change this to an alter statement
 			yyVAL.statement = &DDL{Action: AlterStr, Table: yyDollar[7].sqlID, NewName: yyDollar[7].sqlID}
 		}
 	case 23:
 		yyDollar = yyS[yypt-4 : yypt+1]
-		//line sql.y:253
+//line sql.y:254
 		{
 			yyVAL.statement = &DDL{Action: CreateStr, NewName: SQLName(yyDollar[3].sqlID)}
 		}
 	case 24:
 		yyDollar = yyS[yypt-6 : yypt+1]
-		//line sql.y:259
+//line sql.y:260
 		{
 			yyVAL.statement = &DDL{Action: AlterStr, Table: yyDollar[4].sqlID, NewName: yyDollar[4].sqlID}
 		}
 	case 25:
 		yyDollar = yyS[yypt-7 : yypt+1]
-		//line sql.y:263
+//line sql.y:264
 		{
 			// Change this to a rename statement
 			yyVAL.statement = &DDL{Action: RenameStr, Table: yyDollar[4].sqlID, NewName: yyDollar[7].sqlID}
 		}
 	case 26:
 		yyDollar = yyS[yypt-4 : yypt+1]
-		//line sql.y:268
+//line sql.y:269
 		{
 			yyVAL.statement = &DDL{Action: AlterStr, Table: SQLName(yyDollar[3].sqlID), NewName: SQLName(yyDollar[3].sqlID)}
 		}
 	case 27:
 		yyDollar = yyS[yypt-5 : yypt+1]
-		//line sql.y:274
+//line sql.y:275
 		{
 			yyVAL.statement = &DDL{Action: RenameStr, Table: yyDollar[3].sqlID, NewName: yyDollar[5].sqlID}
 		}
 	case 28:
 		yyDollar = yyS[yypt-4 : yypt+1]
-		//line sql.y:280
+//line sql.y:281
 		{
 			yyVAL.statement = &DDL{Action: DropStr, Table: yyDollar[4].sqlID}
 		}
 	case 29:
 		yyDollar = yyS[yypt-5 : yypt+1]
-		//line sql.y:284
+//line sql.y:285
 		{
 			// Change this to an alter statement
 			yyVAL.statement = &DDL{Action: AlterStr, Table: yyDollar[5].sqlID, NewName: yyDollar[5].sqlID}
 		}
 	case 30:
 		yyDollar = yyS[yypt-5 : yypt+1]
-		//line sql.y:289
+//line sql.y:290
 		{
 			yyVAL.statement = &DDL{Action: DropStr, Table: SQLName(yyDollar[4].sqlID)}
 		}
 	case 31:
 		yyDollar = yyS[yypt-3 : yypt+1]
-		//line sql.y:295
+//line sql.y:296
 		{
 			yyVAL.statement = &DDL{Action: AlterStr, Table: yyDollar[3].sqlID, NewName: yyDollar[3].sqlID}
 		}
 	case 32:
 		yyDollar = yyS[yypt-2 : yypt+1]
-		//line sql.y:301
+//line sql.y:302
 		{
 			yyVAL.statement = &Other{}
 		}
 	case 33:
 		yyDollar = yyS[yypt-2 : yypt+1]
-		//line sql.y:305
+//line sql.y:306
 		{
 			yyVAL.statement = &Other{}
 		}
 	case 34:
 		yyDollar = yyS[yypt-2 : yypt+1]
-		//line sql.y:309
+//line sql.y:310
 		{
 			yyVAL.statement = &Other{}
 		}
 	case 35:
 		yyDollar = yyS[yypt-0 : yypt+1]
-		//line sql.y:314
+//line sql.y:315
 		{
 			setAllowComments(yylex, true)
 		}
 	case 36:
 		yyDollar = yyS[yypt-2 : yypt+1]
-		//line sql.y:318
+//line sql.y:319
 		{
 			yyVAL.bytes2 = yyDollar[2].bytes2
 			setAllowComments(yylex, false)
 		}
 	case 37:
 		yyDollar = yyS[yypt-0 : yypt+1]
-		//line sql.y:324
+//line sql.y:325
 		{
 			yyVAL.bytes2 = nil
 		}
 	case 38:
 		yyDollar = yyS[yypt-2 : yypt+1]
-		//line sql.y:328
+//line sql.y:329
 		{
 			yyVAL.bytes2 = append(yyDollar[1].bytes2, yyDollar[2].bytes)
 		}
 	case 39:
 		yyDollar = yyS[yypt-1 : yypt+1]
-		//line sql.y:334
+//line sql.y:335
 		{
 			yyVAL.str = UnionStr
 		}
 	case 40:
 		yyDollar = yyS[yypt-2 : yypt+1]
-		//line sql.y:338
+//line sql.y:339
 		{
 			yyVAL.str = UnionAllStr
 		}
 	case 41:
 		yyDollar = yyS[yypt-1 : yypt+1]
-		//line sql.y:342
+//line sql.y:343
 		{
 			yyVAL.str = SetMinusStr
 		}
 	case 42:
 		yyDollar = yyS[yypt-1 : yypt+1]
-		//line sql.y:346
+//line sql.y:347
 		{
 			yyVAL.str = ExceptStr
 		}
 	case 43:
 		yyDollar = yyS[yypt-1 : yypt+1]
-		//line sql.y:350
+//line sql.y:351
 		{
 			yyVAL.str = IntersectStr
 		}
 	case 44:
 		yyDollar = yyS[yypt-0 : yypt+1]
-		//line sql.y:355
+//line sql.y:356
 		{
 			yyVAL.str = ""
 		}
 	case 45:
 		yyDollar = yyS[yypt-1 : yypt+1]
-		//line sql.y:359
+//line sql.y:360
 		{
 			yyVAL.str = DistinctStr
 		}
 	case 46:
 		yyDollar = yyS[yypt-1 : yypt+1]
-		//line sql.y:365
+//line sql.y:366
 		{
 			yyVAL.selectExprs = SelectExprs{yyDollar[1].selectExpr}
 		}
 	case 47:
 		yyDollar = yyS[yypt-3 : yypt+1]
-		//line sql.y:369
+//line sql.y:370
 		{
 			yyVAL.selectExprs = append(yyVAL.selectExprs, yyDollar[3].selectExpr)
 		}
 	case 48:
 		yyDollar = yyS[yypt-1 : yypt+1]
-		//line sql.y:375
+//line sql.y:376
 		{
 			yyVAL.selectExpr = &StarExpr{}
 		}
 	case 49:
 		yyDollar = yyS[yypt-2 : yypt+1]
-		//line sql.y:379
+//line sql.y:380
 		{
 			yyVAL.selectExpr = &NonStarExpr{Expr: yyDollar[1].expr, As: yyDollar[2].sqlID}
 		}
 	case 50:
 		yyDollar = yyS[yypt-3 : yypt+1]
-		//line sql.y:383
+//line sql.y:384
 		{
 			yyVAL.selectExpr = &StarExpr{TableName: yyDollar[1].sqlID}
 		}
 	case 51:
 		yyDollar = yyS[yypt-1 : yypt+1]
-		//line sql.y:389
+//line sql.y:390
 		{
 			yyVAL.expr = yyDollar[1].boolExpr
 		}
 	case 52:
 		yyDollar = yyS[yypt-1 : yypt+1]
-		//line sql.y:393
+//line sql.y:394
 		{
 			yyVAL.expr = yyDollar[1].valExpr
 		}
 	case 53:
 		yyDollar = yyS[yypt-0 : yypt+1]
-		//line sql.y:398
+//line sql.y:399
 		{
 			yyVAL.sqlID = ""
 		}
 	case 54:
 		yyDollar = yyS[yypt-1 : yypt+1]
-		//line sql.y:402
+//line sql.y:403
 		{
 			yyVAL.sqlID = yyDollar[1].sqlID
 		}
 	case 55:
 		yyDollar = yyS[yypt-2 : yypt+1]
-		//line sql.y:406
+//line sql.y:407
 		{
 			yyVAL.sqlID = yyDollar[2].sqlID
 		}
 	case 56:
 		yyDollar = yyS[yypt-1 : yypt+1]
-		//line sql.y:412
+//line sql.y:413
 		{
 			yyVAL.tableExprs = TableExprs{yyDollar[1].tableExpr}
 		}
 	case 57:
 		yyDollar = yyS[yypt-3 : yypt+1]
-		//line sql.y:416
+//line sql.y:417
 		{
 			yyVAL.tableExprs = append(yyVAL.tableExprs, yyDollar[3].tableExpr)
 		}
 	case 60:
 		yyDollar = yyS[yypt-3 : yypt+1]
-		//line sql.y:426
+//line sql.y:427
 		{
 			yyVAL.tableExpr = &AliasedTableExpr{Expr: yyDollar[1].smTableExpr, As: yyDollar[2].sqlID, Hints: yyDollar[3].indexHints}
 		}
 	case 61:
 		yyDollar = yyS[yypt-3 : yypt+1]
-		//line sql.y:430
+//line sql.y:431
 		{
 			yyVAL.tableExpr = &AliasedTableExpr{Expr: yyDollar[1].subquery, As: yyDollar[3].sqlID}
 		}
 	case 62:
 		yyDollar = yyS[yypt-3 : yypt+1]
-		//line sql.y:434
+//line sql.y:435
 		{
 			yyVAL.tableExpr = &ParenTableExpr{Exprs: yyDollar[2].tableExprs}
 		}
 	case 63:
 		yyDollar = yyS[yypt-3 : yypt+1]
-		//line sql.y:447
+//line sql.y:448
 		{
 			yyVAL.tableExpr = &JoinTableExpr{LeftExpr: yyDollar[1].tableExpr, Join: yyDollar[2].str, RightExpr: yyDollar[3].tableExpr}
 		}
 	case 64:
 		yyDollar = yyS[yypt-5 : yypt+1]
-		//line sql.y:451
+//line sql.y:452
 		{
 			yyVAL.tableExpr = &JoinTableExpr{LeftExpr: yyDollar[1].tableExpr, Join: yyDollar[2].str, RightExpr: yyDollar[3].tableExpr, On: yyDollar[5].boolExpr}
 		}
 	case 65:
 		yyDollar = yyS[yypt-5 : yypt+1]
-		//line sql.y:455
+//line sql.y:456
 		{
 			yyVAL.tableExpr = &JoinTableExpr{LeftExpr: yyDollar[1].tableExpr, Join: yyDollar[2].str, RightExpr: yyDollar[3].tableExpr, On: yyDollar[5].boolExpr}
 		}
 	case 66:
 		yyDollar = yyS[yypt-3 : yypt+1]
-		//line sql.y:459
+//line sql.y:460
 		{
 			yyVAL.tableExpr = &JoinTableExpr{LeftExpr: yyDollar[1].tableExpr, Join: yyDollar[2].str, RightExpr: yyDollar[3].tableExpr}
 		}
 	case 67:
 		yyDollar = yyS[yypt-0 : yypt+1]
-		//line sql.y:464
+//line sql.y:465
 		{
 			yyVAL.empty = struct{}{}
 		}
 	case 68:
 		yyDollar = yyS[yypt-1 : yypt+1]
-		//line sql.y:466
+//line sql.y:467
 		{
 			yyVAL.empty = struct{}{}
 		}
 	case 69:
 		yyDollar = yyS[yypt-0 : yypt+1]
-		//line sql.y:469
+//line sql.y:470
 		{
 			yyVAL.sqlID = ""
 		}
 	case 70:
 		yyDollar = yyS[yypt-1 : yypt+1]
-		//line sql.y:473
+//line sql.y:474
 		{
 			yyVAL.sqlID = yyDollar[1].sqlID
 		}
 	case 71:
 		yyDollar = yyS[yypt-2 : yypt+1]
-		//line sql.y:477
+//line sql.y:478
 		{
 			yyVAL.sqlID = yyDollar[2].sqlID
 		}
 	case 72:
 		yyDollar = yyS[yypt-1 : yypt+1]
-		//line sql.y:483
+//line sql.y:484
 		{
 			yyVAL.str = JoinStr
 		}
 	case 73:
 		yyDollar = yyS[yypt-2 : yypt+1]
-		//line sql.y:487
+//line sql.y:488
 		{
 			yyVAL.str = JoinStr
 		}
 	case 74:
 		yyDollar = yyS[yypt-2 : yypt+1]
-		//line sql.y:491
+//line sql.y:492
 		{
 			yyVAL.str = JoinStr
 		}
 	case 75:
 		yyDollar = yyS[yypt-1 : yypt+1]
-		//line sql.y:495
+//line sql.y:496
 		{
 			yyVAL.str = StraightJoinStr
 		}
 	case 76:
 		yyDollar = yyS[yypt-2 : yypt+1]
-		//line sql.y:501
+//line sql.y:502
 		{
 			yyVAL.str = LeftJoinStr
 		}
 	case 77:
 		yyDollar = yyS[yypt-3 : yypt+1]
-		//line sql.y:505
+//line sql.y:506
 		{
 			yyVAL.str = LeftJoinStr
 		}
 	case 78:
 		yyDollar = yyS[yypt-2 : yypt+1]
-		//line sql.y:509
+//line sql.y:510
 		{
 			yyVAL.str = RightJoinStr
 		}
 	case 79:
 		yyDollar = yyS[yypt-3 : yypt+1]
-		//line sql.y:513
+//line sql.y:514
 		{
 			yyVAL.str = RightJoinStr
 		}
 	case 80:
 		yyDollar = yyS[yypt-2 : yypt+1]
-		//line sql.y:519
+//line sql.y:520
 		{
 			yyVAL.str = NaturalJoinStr
 		}
 	case 81:
 		yyDollar = yyS[yypt-2 : yypt+1]
-		//line sql.y:523
+//line sql.y:524
 		{
 			if yyDollar[2].str == LeftJoinStr {
 				yyVAL.str = NaturalLeftJoinStr
@@ -1394,385 +1432,385 @@ yydefault:
 		}
 	case 82:
 		yyDollar = yyS[yypt-1 : yypt+1]
-		//line sql.y:533
+//line sql.y:534
 		{
 			yyVAL.smTableExpr = &TableName{Name: yyDollar[1].sqlID}
 		}
 	case 83:
 		yyDollar = yyS[yypt-3 : yypt+1]
-		//line sql.y:537
+//line sql.y:538
 		{
 			yyVAL.smTableExpr = &TableName{Qualifier: yyDollar[1].sqlID, Name: yyDollar[3].sqlID}
 		}
 	case 84:
 		yyDollar = yyS[yypt-1 : yypt+1]
-		//line sql.y:543
+//line sql.y:544
 		{
 			yyVAL.tableName = &TableName{Name: yyDollar[1].sqlID}
 		}
 	case 85:
 		yyDollar = yyS[yypt-3 : yypt+1]
-		//line sql.y:547
+//line sql.y:548
 		{
 			yyVAL.tableName = &TableName{Qualifier: yyDollar[1].sqlID, Name: yyDollar[3].sqlID}
 		}
 	case 86:
 		yyDollar = yyS[yypt-0 : yypt+1]
-		//line sql.y:552
+//line sql.y:553
 		{
 			yyVAL.indexHints = nil
 		}
 	case 87:
 		yyDollar = yyS[yypt-5 : yypt+1]
-		//line sql.y:556
+//line sql.y:557
 		{
 			yyVAL.indexHints = &IndexHints{Type: UseStr, Indexes: yyDollar[4].sqlIDs}
 		}
 	case 88:
 		yyDollar = yyS[yypt-5 : yypt+1]
-		//line sql.y:560
+//line sql.y:561
 		{
 			yyVAL.indexHints = &IndexHints{Type: IgnoreStr, Indexes: yyDollar[4].sqlIDs}
 		}
 	case 89:
 		yyDollar = yyS[yypt-5 : yypt+1]
-		//line sql.y:564
+//line sql.y:565
 		{
 			yyVAL.indexHints = &IndexHints{Type: ForceStr, Indexes: yyDollar[4].sqlIDs}
 		}
 	case 90:
 		yyDollar = yyS[yypt-1 : yypt+1]
-		//line sql.y:570
+//line sql.y:571
 		{
 			yyVAL.sqlIDs = []SQLName{yyDollar[1].sqlID}
 		}
 	case 91:
 		yyDollar = yyS[yypt-3 : yypt+1]
-		//line sql.y:574
+//line sql.y:575
 		{
 			yyVAL.sqlIDs = append(yyDollar[1].sqlIDs, yyDollar[3].sqlID)
 		}
 	case 92:
 		yyDollar = yyS[yypt-0 : yypt+1]
-		//line sql.y:579
+//line sql.y:580
 		{
 			yyVAL.boolExpr = nil
 		}
 	case 93:
 		yyDollar = yyS[yypt-2 : yypt+1]
-		//line sql.y:583
+//line sql.y:584
 		{
 			yyVAL.boolExpr = yyDollar[2].boolExpr
 		}
 	case 95:
 		yyDollar = yyS[yypt-3 : yypt+1]
-		//line sql.y:590
+//line sql.y:591
 		{
 			yyVAL.boolExpr = &AndExpr{Left: yyDollar[1].boolExpr, Right: yyDollar[3].boolExpr}
 		}
 	case 96:
 		yyDollar = yyS[yypt-3 : yypt+1]
-		//line sql.y:594
+//line sql.y:595
 		{
 			yyVAL.boolExpr = &OrExpr{Left: yyDollar[1].boolExpr, Right: yyDollar[3].boolExpr}
 		}
 	case 97:
 		yyDollar = yyS[yypt-2 : yypt+1]
-		//line sql.y:598
+//line sql.y:599
 		{
 			yyVAL.boolExpr = &NotExpr{Expr: yyDollar[2].boolExpr}
 		}
 	case 98:
 		yyDollar = yyS[yypt-3 : yypt+1]
-		//line sql.y:602
+//line sql.y:603
 		{
 			yyVAL.boolExpr = &ParenBoolExpr{Expr: yyDollar[2].boolExpr}
 		}
 	case 99:
 		yyDollar = yyS[yypt-3 : yypt+1]
-		//line sql.y:606
+//line sql.y:607
 		{
 			yyVAL.boolExpr = &IsExpr{Operator: yyDollar[3].str, Expr: yyDollar[1].boolExpr}
 		}
 	case 100:
 		yyDollar = yyS[yypt-1 : yypt+1]
-		//line sql.y:612
+//line sql.y:613
 		{
 			yyVAL.boolExpr = BoolVal(true)
 		}
 	case 101:
 		yyDollar = yyS[yypt-1 : yypt+1]
-		//line sql.y:616
+//line sql.y:617
 		{
 			yyVAL.boolExpr = BoolVal(false)
 		}
 	case 102:
 		yyDollar = yyS[yypt-3 : yypt+1]
-		//line sql.y:620
+//line sql.y:621
 		{
 			yyVAL.boolExpr = &ComparisonExpr{Left: yyDollar[1].valExpr, Operator: yyDollar[2].str, Right: yyDollar[3].valExpr}
 		}
 	case 103:
 		yyDollar = yyS[yypt-3 : yypt+1]
-		//line sql.y:624
+//line sql.y:625
 		{
 			yyVAL.boolExpr = &ComparisonExpr{Left: yyDollar[1].valExpr, Operator: InStr, Right: yyDollar[3].colTuple}
 		}
 	case 104:
 		yyDollar = yyS[yypt-4 : yypt+1]
-		//line sql.y:628
+//line sql.y:629
 		{
 			yyVAL.boolExpr = &ComparisonExpr{Left: yyDollar[1].valExpr, Operator: NotInStr, Right: yyDollar[4].colTuple}
 		}
 	case 105:
 		yyDollar = yyS[yypt-3 : yypt+1]
-		//line sql.y:632
+//line sql.y:633
 		{
 			yyVAL.boolExpr = &ComparisonExpr{Left: yyDollar[1].valExpr, Operator: LikeStr, Right: yyDollar[3].valExpr}
 		}
 	case 106:
 		yyDollar = yyS[yypt-4 : yypt+1]
-		//line sql.y:636
+//line sql.y:637
 		{
 			yyVAL.boolExpr = &ComparisonExpr{Left: yyDollar[1].valExpr, Operator: NotLikeStr, Right: yyDollar[4].valExpr}
 		}
 	case 107:
 		yyDollar = yyS[yypt-3 : yypt+1]
-		//line sql.y:640
+//line sql.y:641
 		{
 			yyVAL.boolExpr = &ComparisonExpr{Left: yyDollar[1].valExpr, Operator: RegexpStr, Right: yyDollar[3].valExpr}
 		}
 	case 108:
 		yyDollar = yyS[yypt-4 : yypt+1]
-		//line sql.y:644
+//line sql.y:645
 		{
 			yyVAL.boolExpr = &ComparisonExpr{Left: yyDollar[1].valExpr, Operator: NotRegexpStr, Right: yyDollar[4].valExpr}
 		}
 	case 109:
 		yyDollar = yyS[yypt-5 : yypt+1]
-		//line sql.y:648
+//line sql.y:649
 		{
 			yyVAL.boolExpr = &RangeCond{Left: yyDollar[1].valExpr, Operator: BetweenStr, From: yyDollar[3].valExpr, To: yyDollar[5].valExpr}
 		}
 	case 110:
 		yyDollar = yyS[yypt-6 : yypt+1]
-		//line sql.y:652
+//line sql.y:653
 		{
 			yyVAL.boolExpr = &RangeCond{Left: yyDollar[1].valExpr, Operator: NotBetweenStr, From: yyDollar[4].valExpr, To: yyDollar[6].valExpr}
 		}
 	case 111:
 		yyDollar = yyS[yypt-3 : yypt+1]
-		//line sql.y:656
+//line sql.y:657
 		{
 			yyVAL.boolExpr = &IsExpr{Operator: yyDollar[3].str, Expr: yyDollar[1].valExpr}
 		}
 	case 112:
 		yyDollar = yyS[yypt-2 : yypt+1]
-		//line sql.y:660
+//line sql.y:661
 		{
 			yyVAL.boolExpr = &ExistsExpr{Subquery: yyDollar[2].subquery}
 		}
 	case 113:
 		yyDollar = yyS[yypt-6 : yypt+1]
-		//line sql.y:664
+//line sql.y:665
 		{
 			yyVAL.boolExpr = &KeyrangeExpr{Start: yyDollar[3].valExpr, End: yyDollar[5].valExpr}
 		}
 	case 114:
 		yyDollar = yyS[yypt-1 : yypt+1]
-		//line sql.y:670
+//line sql.y:671
 		{
 			yyVAL.str = IsNullStr
 		}
 	case 115:
 		yyDollar = yyS[yypt-2 : yypt+1]
-		//line sql.y:674
+//line sql.y:675
 		{
 			yyVAL.str = IsNotNullStr
 		}
 	case 116:
 		yyDollar = yyS[yypt-1 : yypt+1]
-		//line sql.y:678
+//line sql.y:679
 		{
 			yyVAL.str = IsTrueStr
 		}
 	case 117:
 		yyDollar = yyS[yypt-2 : yypt+1]
-		//line sql.y:682
+//line sql.y:683
 		{
 			yyVAL.str = IsNotTrueStr
 		}
 	case 118:
 		yyDollar = yyS[yypt-1 : yypt+1]
-		//line sql.y:686
+//line sql.y:687
 		{
 			yyVAL.str = IsFalseStr
 		}
 	case 119:
 		yyDollar = yyS[yypt-2 : yypt+1]
-		//line sql.y:690
+//line sql.y:691
 		{
 			yyVAL.str = IsNotFalseStr
 		}
 	case 120:
 		yyDollar = yyS[yypt-1 : yypt+1]
-		//line sql.y:696
+//line sql.y:697
 		{
 			yyVAL.str = EqualStr
 		}
 	case 121:
 		yyDollar = yyS[yypt-1 : yypt+1]
-		//line sql.y:700
+//line sql.y:701
 		{
 			yyVAL.str = LessThanStr
 		}
 	case 122:
 		yyDollar = yyS[yypt-1 : yypt+1]
-		//line sql.y:704
+//line sql.y:705
 		{
 			yyVAL.str = GreaterThanStr
 		}
 	case 123:
 		yyDollar = yyS[yypt-1 : yypt+1]
-		//line sql.y:708
+//line sql.y:709
 		{
 			yyVAL.str = LessEqualStr
 		}
 	case 124:
 		yyDollar = yyS[yypt-1 : yypt+1]
-		//line sql.y:712
+//line sql.y:713
 		{
 			yyVAL.str = GreaterEqualStr
 		}
 	case 125:
 		yyDollar = yyS[yypt-1 : yypt+1]
-		//line sql.y:716
+//line sql.y:717
 		{
 			yyVAL.str = NotEqualStr
 		}
 	case 126:
 		yyDollar = yyS[yypt-1 : yypt+1]
-		//line sql.y:720
+//line sql.y:721
 		{
 			yyVAL.str = NullSafeEqualStr
 		}
 	case 127:
 		yyDollar = yyS[yypt-3 : yypt+1]
-		//line sql.y:726
+//line sql.y:727
 		{
 			yyVAL.colTuple = ValTuple(yyDollar[2].valExprs)
 		}
 	case 128:
 		yyDollar = yyS[yypt-1 : yypt+1]
-		//line sql.y:730
+//line sql.y:731
 		{
 			yyVAL.colTuple = yyDollar[1].subquery
 		}
 	case 129:
 		yyDollar = yyS[yypt-1 : yypt+1]
-		//line sql.y:734
+//line sql.y:735
 		{
 			yyVAL.colTuple = ListArg(yyDollar[1].bytes)
 		}
 	case 130:
 		yyDollar = yyS[yypt-3 : yypt+1]
-		//line sql.y:740
+//line sql.y:741
 		{
 			yyVAL.subquery = &Subquery{yyDollar[2].selStmt}
 		}
 	case 131:
 		yyDollar = yyS[yypt-1 : yypt+1]
-		//line sql.y:746
+//line sql.y:747
 		{
 			yyVAL.valExprs = ValExprs{yyDollar[1].valExpr}
 		}
 	case 132:
 		yyDollar = yyS[yypt-3 : yypt+1]
-		//line sql.y:750
+//line sql.y:751
 		{
 			yyVAL.valExprs = append(yyDollar[1].valExprs, yyDollar[3].valExpr)
 		}
 	case 133:
 		yyDollar = yyS[yypt-1 : yypt+1]
-		//line sql.y:756
+//line sql.y:757
 		{
 			yyVAL.valExpr = yyDollar[1].valExpr
 		}
 	case 134:
 		yyDollar = yyS[yypt-1 : yypt+1]
-		//line sql.y:760
+//line sql.y:761
 		{
 			yyVAL.valExpr = yyDollar[1].colName
 		}
 	case 135:
 		yyDollar = yyS[yypt-1 : yypt+1]
-		//line sql.y:764
+//line sql.y:765
 		{
 			yyVAL.valExpr = yyDollar[1].rowTuple
 		}
 	case 136:
 		yyDollar = yyS[yypt-3 : yypt+1]
-		//line sql.y:768
+//line sql.y:769
 		{
 			yyVAL.valExpr = &BinaryExpr{Left: yyDollar[1].valExpr, Operator: BitAndStr, Right: yyDollar[3].valExpr}
 		}
 	case 137:
 		yyDollar = yyS[yypt-3 : yypt+1]
-		//line sql.y:772
+//line sql.y:773
 		{
 			yyVAL.valExpr = &BinaryExpr{Left: yyDollar[1].valExpr, Operator: BitOrStr, Right: yyDollar[3].valExpr}
 		}
 	case 138:
 		yyDollar = yyS[yypt-3 : yypt+1]
-		//line sql.y:776
+//line sql.y:777
 		{
 			yyVAL.valExpr = &BinaryExpr{Left: yyDollar[1].valExpr, Operator: BitXorStr, Right: yyDollar[3].valExpr}
 		}
 	case 139:
 		yyDollar = yyS[yypt-3 : yypt+1]
-		//line sql.y:780
+//line sql.y:781
 		{
 			yyVAL.valExpr = &BinaryExpr{Left: yyDollar[1].valExpr, Operator: PlusStr, Right: yyDollar[3].valExpr}
 		}
 	case 140:
 		yyDollar = yyS[yypt-3 : yypt+1]
-		//line sql.y:784
+//line sql.y:785
 		{
 			yyVAL.valExpr = &BinaryExpr{Left: yyDollar[1].valExpr, Operator: MinusStr, Right: yyDollar[3].valExpr}
 		}
 	case 141:
 		yyDollar = yyS[yypt-3 : yypt+1]
-		//line sql.y:788
+//line sql.y:789
 		{
 			yyVAL.valExpr = &BinaryExpr{Left: yyDollar[1].valExpr, Operator: MultStr, Right: yyDollar[3].valExpr}
 		}
 	case 142:
 		yyDollar = yyS[yypt-3 : yypt+1]
-		//line sql.y:792
+//line sql.y:793
 		{
 			yyVAL.valExpr = &BinaryExpr{Left: yyDollar[1].valExpr, Operator: DivStr, Right: yyDollar[3].valExpr}
 		}
 	case 143:
 		yyDollar = yyS[yypt-3 : yypt+1]
-		//line sql.y:796
+//line sql.y:797
 		{
 			yyVAL.valExpr = &BinaryExpr{Left: yyDollar[1].valExpr, Operator: ModStr, Right: yyDollar[3].valExpr}
 		}
 	case 144:
 		yyDollar = yyS[yypt-3 : yypt+1]
-		//line sql.y:800
+//line sql.y:801
 		{
 			yyVAL.valExpr = &BinaryExpr{Left: yyDollar[1].valExpr, Operator: ShiftLeftStr, Right: yyDollar[3].valExpr}
 		}
 	case 145:
 		yyDollar = yyS[yypt-3 : yypt+1]
-		//line sql.y:804
+//line sql.y:805
 		{
 			yyVAL.valExpr = &BinaryExpr{Left: yyDollar[1].valExpr, Operator: ShiftRightStr, Right: yyDollar[3].valExpr}
 		}
 	case 146:
 		yyDollar = yyS[yypt-2 : yypt+1]
-		//line sql.y:808
+//line sql.y:809
 		{
 			if num, ok := yyDollar[2].valExpr.(NumVal); ok {
 				yyVAL.valExpr = num
@@ -1782,7 +1820,7 @@ yydefault:
 		}
 	case 147:
 		yyDollar = yyS[yypt-2 : yypt+1]
-		//line sql.y:816
+//line sql.y:817
 		{
 			if num, ok := yyDollar[2].valExpr.(NumVal); ok {
 				// Handle double negative
@@ -1797,13 +1835,13 @@ yydefault:
 		}
 	case 148:
 		yyDollar = yyS[yypt-2 : yypt+1]
-		//line sql.y:829
+//line sql.y:830
 		{
 			yyVAL.valExpr = &UnaryExpr{Operator: TildaStr, Expr: yyDollar[2].valExpr}
 		}
 	case 149:
 		yyDollar = yyS[yypt-3 : yypt+1]
-		//line sql.y:833
+//line sql.y:834
 		{
 			// This rule prevents the usage of INTERVAL
 			// as a function. If support is needed for that,
@@ -1813,223 +1851,259 @@ yydefault:
 		}
 	case 150:
 		yyDollar = yyS[yypt-3 : yypt+1]
-		//line sql.y:841
+//line sql.y:842
 		{
 			yyVAL.valExpr = &FuncExpr{Name: string(yyDollar[1].sqlID)}
 		}
 	case 151:
 		yyDollar = yyS[yypt-4 : yypt+1]
-		//line sql.y:845
+//line sql.y:846
 		{
 			yyVAL.valExpr = &FuncExpr{Name: string(yyDollar[1].sqlID), Exprs: yyDollar[3].selectExprs}
 		}
 	case 152:
 		yyDollar = yyS[yypt-5 : yypt+1]
-		//line sql.y:849
+//line sql.y:850
 		{
 			yyVAL.valExpr = &FuncExpr{Name: string(yyDollar[1].sqlID), Distinct: true, Exprs: yyDollar[4].selectExprs}
 		}
 	case 153:
 		yyDollar = yyS[yypt-4 : yypt+1]
-		//line sql.y:853
+//line sql.y:854
 		{
 			yyVAL.valExpr = &FuncExpr{Name: "if", Exprs: yyDollar[3].selectExprs}
 		}
 	case 154:
 		yyDollar = yyS[yypt-1 : yypt+1]
-		//line sql.y:857
+//line sql.y:858
 		{
 			yyVAL.valExpr = yyDollar[1].caseExpr
 		}
 	case 155:
 		yyDollar = yyS[yypt-5 : yypt+1]
-		//line sql.y:863
+//line sql.y:864
 		{
 			yyVAL.caseExpr = &CaseExpr{Expr: yyDollar[2].valExpr, Whens: yyDollar[3].whens, Else: yyDollar[4].valExpr}
 		}
 	case 156:
 		yyDollar = yyS[yypt-0 : yypt+1]
-		//line sql.y:868
+//line sql.y:869
 		{
 			yyVAL.valExpr = nil
 		}
 	case 157:
 		yyDollar = yyS[yypt-1 : yypt+1]
-		//line sql.y:872
+//line sql.y:873
 		{
 			yyVAL.valExpr = yyDollar[1].valExpr
 		}
 	case 158:
 		yyDollar = yyS[yypt-1 : yypt+1]
-		//line sql.y:878
+//line sql.y:879
 		{
 			yyVAL.whens = []*When{yyDollar[1].when}
 		}
 	case 159:
 		yyDollar = yyS[yypt-2 : yypt+1]
-		//line sql.y:882
+//line sql.y:883
 		{
 			yyVAL.whens = append(yyDollar[1].whens, yyDollar[2].when)
 		}
 	case 160:
 		yyDollar = yyS[yypt-4 : yypt+1]
-		//line sql.y:888
+//line sql.y:889
 		{
 			yyVAL.when = &When{Cond: yyDollar[2].boolExpr, Val: yyDollar[4].valExpr}
 		}
 	case 161:
 		yyDollar = yyS[yypt-0 : yypt+1]
-		//line sql.y:893
+//line sql.y:894
 		{
 			yyVAL.valExpr = nil
 		}
 	case 162:
 		yyDollar = yyS[yypt-2 : yypt+1]
-		//line sql.y:897
+//line sql.y:898
 		{
 			yyVAL.valExpr = yyDollar[2].valExpr
 		}
 	case 163:
 		yyDollar = yyS[yypt-1 : yypt+1]
-		//line sql.y:903
+//line sql.y:904
 		{
 			yyVAL.colName = &ColName{Name: yyDollar[1].sqlID}
 		}
 	case 164:
 		yyDollar = yyS[yypt-3 : yypt+1]
-		//line sql.y:907
+//line sql.y:908
 		{
 			yyVAL.colName = &ColName{Qualifier: yyDollar[1].sqlID, Name: yyDollar[3].sqlID}
 		}
 	case 165:
 		yyDollar = yyS[yypt-1 : yypt+1]
-		//line sql.y:913
+//line sql.y:914
 		{
 			yyVAL.valExpr = StrVal(yyDollar[1].bytes)
 		}
 	case 166:
 		yyDollar = yyS[yypt-1 : yypt+1]
-		//line sql.y:917
+//line sql.y:918
 		{
 			yyVAL.valExpr = NumVal(yyDollar[1].bytes)
 		}
 	case 167:
 		yyDollar = yyS[yypt-1 : yypt+1]
-		//line sql.y:921
+//line sql.y:922
 		{
 			yyVAL.valExpr = ValArg(yyDollar[1].bytes)
 		}
 	case 168:
 		yyDollar = yyS[yypt-1 : yypt+1]
-		//line sql.y:925
+//line sql.y:926
 		{
 			yyVAL.valExpr = &NullVal{}
 		}
 	case 169:
 		yyDollar = yyS[yypt-0 : yypt+1]
-		//line sql.y:930
+//line sql.y:931
 		{
 			yyVAL.valExprs = nil
 		}
 	case 170:
 		yyDollar = yyS[yypt-3 : yypt+1]
-		//line sql.y:934
+//line sql.y:935
 		{
 			yyVAL.valExprs = yyDollar[3].valExprs
 		}
 	case 171:
 		yyDollar = yyS[yypt-0 : yypt+1]
-		//line sql.y:939
+//line sql.y:940
 		{
 			yyVAL.boolExpr = nil
 		}
 	case 172:
 		yyDollar = yyS[yypt-2 : yypt+1]
-		//line sql.y:943
+//line sql.y:944
 		{
 			yyVAL.boolExpr = yyDollar[2].boolExpr
 		}
 	case 173:
 		yyDollar = yyS[yypt-0 : yypt+1]
-		//line sql.y:948
+//line sql.y:949
 		{
 			yyVAL.orderBy = nil
 		}
 	case 174:
 		yyDollar = yyS[yypt-3 : yypt+1]
-		//line sql.y:952
+//line sql.y:953
 		{
 			yyVAL.orderBy = yyDollar[3].orderBy
 		}
 	case 175:
 		yyDollar = yyS[yypt-1 : yypt+1]
-		//line sql.y:958
+//line sql.y:959
 		{
 			yyVAL.orderBy = OrderBy{yyDollar[1].order}
 		}
 	case 176:
 		yyDollar = yyS[yypt-3 : yypt+1]
-		//line sql.y:962
+//line sql.y:963
 		{
 			yyVAL.orderBy = append(yyDollar[1].orderBy, yyDollar[3].order)
 		}
 	case 177:
-		yyDollar = yyS[yypt-2 : yypt+1]
-		//line sql.y:968
+		yyDollar = yyS[yypt-3 : yypt+1]
+//line sql.y:969
 		{
-			yyVAL.order = &Order{Expr: yyDollar[1].valExpr, Direction: yyDollar[2].str}
+			yyVAL.order = &Order{Expr: yyDollar[1].valExpr, Direction: yyDollar[2].str, Nulls: yyDollar[3].str}
 		}
 	case 178:
 		yyDollar = yyS[yypt-0 : yypt+1]
-		//line sql.y:973
+//line sql.y:974
 		{
 			yyVAL.str = AscScr
 		}
 	case 179:
 		yyDollar = yyS[yypt-1 : yypt+1]
-		//line sql.y:977
+//line sql.y:978
 		{
 			yyVAL.str = AscScr
 		}
 	case 180:
 		yyDollar = yyS[yypt-1 : yypt+1]
-		//line sql.y:981
+//line sql.y:982
 		{
 			yyVAL.str = DescScr
 		}
 	case 181:
 		yyDollar = yyS[yypt-0 : yypt+1]
-		//line sql.y:986
+//line sql.y:987
 		{
-			yyVAL.limit = nil
+			yyVAL.str = ""
 		}
 	case 182:
 		yyDollar = yyS[yypt-2 : yypt+1]
-		//line sql.y:990
+//line sql.y:991
 		{
-			yyVAL.limit = &Limit{Rowcount: yyDollar[2].valExpr}
+			yyVAL.str = NullsFirstStr
 		}
 	case 183:
+		yyDollar = yyS[yypt-2 : yypt+1]
+//line sql.y:995
+		{
+			yyVAL.str = NullsLastStr
+		}
+	case 184:
+		yyDollar = yyS[yypt-0 : yypt+1]
+//line sql.y:1000
+		{
+			yyVAL.limit = nil
+		}
+	case 185:
+		yyDollar = yyS[yypt-2 : yypt+1]
+//line sql.y:1004
+		{
+			yyVAL.limit = &Limit{Rowcount: yyDollar[2].valExpr}
+		}
+	case 186:
 		yyDollar = yyS[yypt-4 : yypt+1]
-		//line sql.y:994
+//line sql.y:1008
 		{
 			yyVAL.limit = &Limit{Offset: yyDollar[2].valExpr, Rowcount: yyDollar[4].valExpr}
 		}
-	case 184:
+	case 187:
+		yyDollar = yyS[yypt-4 : yypt+1]
+//line sql.y:1012
+		{
+			yyVAL.limit = &Limit{Offset: yyDollar[4].valExpr, Rowcount: yyDollar[2].valExpr}
+		}
+	case 188:
+		yyDollar = yyS[yypt-2 : yypt+1]
+//line sql.y:1016
+		{
+			yyVAL.limit = &Limit{Offset: yyDollar[2].valExpr}
+		}
+	case 189:
+		yyDollar = yyS[yypt-4 : yypt+1]
+//line sql.y:1020
+		{
+			yyVAL.limit = &Limit{Offset: yyDollar[2].valExpr, Rowcount: yyDollar[4].valExpr}
+		}
+	case 190:
 		yyDollar = yyS[yypt-0 : yypt+1]
-		//line sql.y:999
+//line sql.y:1025
 		{
 			yyVAL.str = ""
 		}
-	case 185:
+	case 191:
 		yyDollar = yyS[yypt-2 : yypt+1]
-		//line sql.y:1003
+//line sql.y:1029
 		{
 			yyVAL.str = ForUpdateStr
 		}
-	case 186:
+	case 192:
 		yyDollar = yyS[yypt-4 : yypt+1]
-		//line sql.y:1007
+//line sql.y:1033
 		{
 			if yyDollar[3].sqlID != "share" {
 				yylex.Error("expecting share")
@@ -2041,228 +2115,276 @@ yydefault:
 			}
 			yyVAL.str = ShareModeStr
 		}
-	case 187:
+	case 193:
 		yyDollar = yyS[yypt-0 : yypt+1]
-		//line sql.y:1020
+//line sql.y:1046
 		{
 			yyVAL.columns = nil
 		}
-	case 188:
+	case 194:
 		yyDollar = yyS[yypt-3 : yypt+1]
-		//line sql.y:1024
+//line sql.y:1050
 		{
 			yyVAL.columns = yyDollar[2].columns
 		}
-	case 189:
+	case 195:
 		yyDollar = yyS[yypt-1 : yypt+1]
-		//line sql.y:1030
+//line sql.y:1056
 		{
 			yyVAL.columns = Columns{&NonStarExpr{Expr: yyDollar[1].colName}}
 		}
-	case 190:
+	case 196:
 		yyDollar = yyS[yypt-3 : yypt+1]
-		//line sql.y:1034
+//line sql.y:1060
 		{
 			yyVAL.columns = append(yyVAL.columns, &NonStarExpr{Expr: yyDollar[3].colName})
 		}
-	case 191:
+	case 197:
 		yyDollar = yyS[yypt-0 : yypt+1]
-		//line sql.y:1039
+//line sql.y:1065
 		{
 			yyVAL.updateExprs = nil
 		}
-	case 192:
+	case 198:
 		yyDollar = yyS[yypt-5 : yypt+1]
-		//line sql.y:1043
+//line sql.y:1069
 		{
 			yyVAL.updateExprs = yyDollar[5].updateExprs
 		}
-	case 193:
+	case 199:
+		yyDollar = yyS[yypt-0 : yypt+1]
+//line sql.y:1074
+		{
+			yyVAL.selectExprs = nil
+		}
+	case 200:
 		yyDollar = yyS[yypt-2 : yypt+1]
-		//line sql.y:1049
+//line sql.y:1078
+		{
+			yyVAL.selectExprs = yyDollar[2].selectExprs
+		}
+	case 201:
+		yyDollar = yyS[yypt-2 : yypt+1]
+//line sql.y:1084
 		{
 			yyVAL.insRows = yyDollar[2].values
 		}
-	case 194:
+	case 202:
 		yyDollar = yyS[yypt-1 : yypt+1]
-		//line sql.y:1053
+//line sql.y:1088
 		{
 			yyVAL.insRows = yyDollar[1].selStmt
 		}
-	case 195:
+	case 203:
 		yyDollar = yyS[yypt-1 : yypt+1]
-		//line sql.y:1059
+//line sql.y:1094
 		{
 			yyVAL.values = Values{yyDollar[1].rowTuple}
 		}
-	case 196:
+	case 204:
 		yyDollar = yyS[yypt-3 : yypt+1]
-		//line sql.y:1063
+//line sql.y:1098
 		{
 			yyVAL.values = append(yyDollar[1].values, yyDollar[3].rowTuple)
 		}
-	case 197:
+	case 205:
 		yyDollar = yyS[yypt-3 : yypt+1]
-		//line sql.y:1069
+//line sql.y:1104
 		{
 			yyVAL.rowTuple = ValTuple(yyDollar[2].valExprs)
 		}
-	case 198:
+	case 206:
 		yyDollar = yyS[yypt-1 : yypt+1]
-		//line sql.y:1073
+//line sql.y:1108
 		{
 			yyVAL.rowTuple = yyDollar[1].subquery
 		}
-	case 199:
+	case 207:
 		yyDollar = yyS[yypt-1 : yypt+1]
-		//line sql.y:1079
+//line sql.y:1114
 		{
 			yyVAL.updateExprs = UpdateExprs{yyDollar[1].updateExpr}
 		}
-	case 200:
+	case 208:
 		yyDollar = yyS[yypt-3 : yypt+1]
-		//line sql.y:1083
+//line sql.y:1118
 		{
 			yyVAL.updateExprs = append(yyDollar[1].updateExprs, yyDollar[3].updateExpr)
 		}
-	case 201:
+	case 209:
 		yyDollar = yyS[yypt-3 : yypt+1]
-		//line sql.y:1089
+//line sql.y:1124
 		{
 			yyVAL.updateExpr = &UpdateExpr{Name: yyDollar[1].colName, Expr: yyDollar[3].valExpr}
 		}
-	case 204:
+	case 212:
 		yyDollar = yyS[yypt-0 : yypt+1]
-		//line sql.y:1098
+//line sql.y:1133
 		{
 			yyVAL.empty = struct{}{}
 		}
-	case 205:
+	case 213:
 		yyDollar = yyS[yypt-2 : yypt+1]
-		//line sql.y:1100
+//line sql.y:1135
 		{
 			yyVAL.empty = struct{}{}
 		}
-	case 206:
+	case 214:
 		yyDollar = yyS[yypt-0 : yypt+1]
-		//line sql.y:1103
+//line sql.y:1138
 		{
 			yyVAL.empty = struct{}{}
 		}
-	case 207:
+	case 215:
 		yyDollar = yyS[yypt-3 : yypt+1]
-		//line sql.y:1105
+//line sql.y:1140
 		{
 			yyVAL.empty = struct{}{}
 		}
-	case 208:
+	case 216:
 		yyDollar = yyS[yypt-0 : yypt+1]
-		//line sql.y:1108
+//line sql.y:1143
 		{
 			yyVAL.str = ""
 		}
-	case 209:
+	case 217:
 		yyDollar = yyS[yypt-1 : yypt+1]
-		//line sql.y:1110
+//line sql.y:1145
 		{
 			yyVAL.str = IgnoreStr
 		}
-	case 210:
+	case 218:
 		yyDollar = yyS[yypt-1 : yypt+1]
-		//line sql.y:1114
+//line sql.y:1149
 		{
 			yyVAL.empty = struct{}{}
 		}
-	case 211:
+	case 219:
 		yyDollar = yyS[yypt-1 : yypt+1]
-		//line sql.y:1116
+//line sql.y:1151
 		{
 			yyVAL.empty = struct{}{}
 		}
-	case 212:
+	case 220:
 		yyDollar = yyS[yypt-1 : yypt+1]
-		//line sql.y:1118
+//line sql.y:1153
 		{
 			yyVAL.empty = struct{}{}
 		}
-	case 213:
+	case 221:
 		yyDollar = yyS[yypt-1 : yypt+1]
-		//line sql.y:1120
+//line sql.y:1155
 		{
 			yyVAL.empty = struct{}{}
 		}
-	case 214:
+	case 222:
 		yyDollar = yyS[yypt-1 : yypt+1]
-		//line sql.y:1122
+//line sql.y:1157
 		{
 			yyVAL.empty = struct{}{}
 		}
-	case 215:
+	case 223:
 		yyDollar = yyS[yypt-0 : yypt+1]
-		//line sql.y:1125
+//line sql.y:1160
 		{
 			yyVAL.empty = struct{}{}
 		}
-	case 216:
+	case 224:
 		yyDollar = yyS[yypt-1 : yypt+1]
-		//line sql.y:1127
+//line sql.y:1162
 		{
 			yyVAL.empty = struct{}{}
 		}
-	case 217:
+	case 225:
 		yyDollar = yyS[yypt-0 : yypt+1]
-		//line sql.y:1130
+//line sql.y:1165
 		{
 			yyVAL.empty = struct{}{}
 		}
-	case 218:
+	case 226:
 		yyDollar = yyS[yypt-1 : yypt+1]
-		//line sql.y:1132
+//line sql.y:1167
 		{
 			yyVAL.empty = struct{}{}
 		}
-	case 219:
+	case 227:
 		yyDollar = yyS[yypt-0 : yypt+1]
-		//line sql.y:1135
+//line sql.y:1170
 		{
 			yyVAL.empty = struct{}{}
 		}
-	case 220:
+	case 228:
 		yyDollar = yyS[yypt-2 : yypt+1]
-		//line sql.y:1137
+//line sql.y:1172
 		{
 			yyVAL.empty = struct{}{}
 		}
-	case 221:
+	case 229:
 		yyDollar = yyS[yypt-1 : yypt+1]
-		//line sql.y:1141
+//line sql.y:1176
 		{
 			yyVAL.sqlID = SQLName(strings.ToLower(string(yyDollar[1].bytes)))
 		}
-	case 222:
+	case 231:
 		yyDollar = yyS[yypt-1 : yypt+1]
-		//line sql.y:1147
+//line sql.y:1187
+		{
+			yyVAL.sqlID = SQLName("returning")
+		}
+	case 232:
+		yyDollar = yyS[yypt-1 : yypt+1]
+//line sql.y:1191
+		{
+			yyVAL.sqlID = SQLName("offset")
+		}
+	case 234:
+		yyDollar = yyS[yypt-1 : yypt+1]
+//line sql.y:1202
+		{
+			yyVAL.sqlID = SQLName("nulls")
+		}
+	case 235:
+		yyDollar = yyS[yypt-1 : yypt+1]
+//line sql.y:1206
+		{
+			yyVAL.sqlID = SQLName("first")
+		}
+	case 236:
+		yyDollar = yyS[yypt-1 : yypt+1]
+//line sql.y:1210
+		{
+			yyVAL.sqlID = SQLName("last")
+		}
+	case 237:
+		yyDollar = yyS[yypt-1 : yypt+1]
+//line sql.y:1216
 		{
 			yyVAL.sqlID = SQLName(yyDollar[1].bytes)
 		}
-	case 223:
+	case 239:
+		yyDollar = yyS[yypt-1 : yypt+1]
+//line sql.y:1223
+		{
+			yyVAL.sqlID = SQLName(yyDollar[1].bytes)
+		}
+	case 241:
 		yyDollar = yyS[yypt-1 : yypt+1]
-		//line sql.y:1153
+//line sql.y:1230
 		{
 			if incNesting(yylex) {
 				yylex.Error("max nesting level reached")
 				return 1
 			}
 		}
-	case 224:
+	case 242:
 		yyDollar = yyS[yypt-1 : yypt+1]
-		//line sql.y:1162
+//line sql.y:1239
 		{
 			decNesting(yylex)
 		}
-	case 225:
+	case 243:
 		yyDollar = yyS[yypt-0 : yypt+1]
-		//line sql.y:1167
+//line sql.y:1244
 		{
 			forceEOF(yylex)
 		}
@@ -73,9 +73,9 @@ func forceEOF(yylex interface{}) {
 
 %token LEX_ERROR
 %left <empty> UNION MINUS EXCEPT INTERSECT
-%token <empty> SELECT INSERT UPDATE DELETE FROM WHERE GROUP HAVING ORDER BY LIMIT FOR
-%token <empty> ALL DISTINCT AS EXISTS ASC DESC INTO DUPLICATE KEY DEFAULT SET LOCK KEYRANGE
-%token <empty> VALUES LAST_INSERT_ID
+%token <empty> SELECT INSERT UPDATE DELETE FROM WHERE GROUP HAVING ORDER BY LIMIT OFFSET FOR
+%token <empty> ALL DISTINCT AS EXISTS ASC DESC NULLS FIRST LAST INTO DUPLICATE KEY DEFAULT SET LOCK KEYRANGE
+%token <empty> VALUES LAST_INSERT_ID RETURNING
 %token <empty> NEXT VALUE
 %left <empty> JOIN STRAIGHT_JOIN LEFT RIGHT INNER OUTER CROSS NATURAL USE FORCE
 %left <empty> ON
@@ -116,7 +116,7 @@ func forceEOF(yylex interface{}) {
 %type <bytes2> comment_opt comment_list
 %type <str> union_op
 %type <str> distinct_opt
-%type <selectExprs> select_expression_list
+%type <selectExprs> select_expression_list returning_opt
 %type <selectExpr> select_expression
 %type <expr> expression
 %type <tableExprs> table_references
@@ -146,7 +146,7 @@ func forceEOF(yylex interface{}) {
 %type <boolExpr> having_opt
 %type <orderBy> order_by_opt order_list
 %type <order> order
-%type <str> asc_desc_opt
+%type <str> asc_desc_opt nulls_opt
 %type <limit> limit_opt
 %type <str> lock_opt
 %type <columns> column_list_opt column_list
@@ -157,6 +157,7 @@ func forceEOF(yylex interface{}) {
 %type <str> ignore_opt
 %type <empty> exists_opt not_exists_opt non_rename_operation to_opt constraint_opt using_opt
 %type <sqlID> sql_id as_lower_opt
+%type <sqlID> non_reserved_keyword non_reserved_alias_keyword table_alias
 %type <sqlID> table_id as_opt_id
 %type <empty> as_opt
 %type <empty> force_eof
@@ -206,11 +207,11 @@ select_statement:
   }
 
 insert_statement:
-  INSERT comment_opt ignore_opt INTO dml_table_expression column_list_opt row_list on_dup_opt
+  INSERT comment_opt ignore_opt INTO dml_table_expression column_list_opt row_list on_dup_opt returning_opt
   {
-    $$ = &Insert{Comments: Comments($2), Ignore: $3, Table: $5, Columns: $6, Rows: $7, OnDup: OnDup($8)}
+    $$ = &Insert{Comments: Comments($2), Ignore: $3, Table: $5, Columns: $6, Rows: $7, OnDup: OnDup($8), Returning: Returning($9)}
   }
-| INSERT comment_opt ignore_opt INTO dml_table_expression SET update_list on_dup_opt
+| INSERT comment_opt ignore_opt INTO dml_table_expression SET update_list on_dup_opt returning_opt
   {
     cols := make(Columns, 0, len($7))
     vals := make(ValTuple, 0, len($7))
@@ -218,19 +219,19 @@ insert_statement:
       cols = append(cols, &NonStarExpr{Expr: col.Name})
       vals = append(vals, col.Expr)
     }
-    $$ = &Insert{Comments: Comments($2), Ignore: $3, Table: $5, Columns: cols, Rows: Values{vals}, OnDup: OnDup($8)}
+    $$ = &Insert{Comments: Comments($2), Ignore: $3, Table: $5, Columns: cols, Rows: Values{vals}, OnDup: OnDup($8), Returning: Returning($9)}
   }
 
 update_statement:
-  UPDATE comment_opt dml_table_expression SET update_list where_expression_opt order_by_opt limit_opt
+  UPDATE comment_opt dml_table_expression SET update_list where_expression_opt order_by_opt limit_opt returning_opt
   {
-    $$ = &Update{Comments: Comments($2), Table: $3, Exprs: $5, Where: NewWhere(WhereStr, $6), OrderBy: $7, Limit: $8}
+    $$ = &Update{Comments: Comments($2), Table: $3, Exprs: $5, Where: NewWhere(WhereStr, $6), OrderBy: $7, Limit: $8, Returning: Returning($9)}
   }
 
 delete_statement:
-  DELETE comment_opt FROM dml_table_expression where_expression_opt order_by_opt limit_opt
+  DELETE comment_opt FROM dml_table_expression where_expression_opt order_by_opt limit_opt returning_opt
   {
-    $$ = &Delete{Comments: Comments($2), Table: $4, Where: NewWhere(WhereStr, $5), OrderBy: $6, Limit: $7}
+    $$ = &Delete{Comments: Comments($2), Table: $4, Where: NewWhere(WhereStr, $5), OrderBy: $6, Limit: $7, Returning: Returning($8)}
   }
 
 set_statement:
@@ -469,7 +470,7 @@ as_opt_id:
   {
     $$ = ""
   }
-| table_id
+| table_alias
   {
     $$ = $1
   }
@@ -964,9 +965,9 @@ order_list:
   }
 
 order:
-  value_expression asc_desc_opt
+  value_expression asc_desc_opt nulls_opt
   {
-    $$ = &Order{Expr: $1, Direction: $2}
+    $$ = &Order{Expr: $1, Direction: $2, Nulls: $3}
   }
 
 asc_desc_opt:
@@ -982,6 +983,19 @@ asc_desc_opt:
     $$ = DescScr
   }
 
+nulls_opt:
+  {
+    $$ = ""
+  }
+| NULLS FIRST
+  {
+    $$ = NullsFirstStr
+  }
+| NULLS LAST
+  {
+    $$ = NullsLastStr
+  }
+
 limit_opt:
   {
     $$ = nil
@@ -994,6 +1008,18 @@ limit_opt:
   {
     $$ = &Limit{Offset: $2, Rowcount: $4}
   }
+| LIMIT value_expression OFFSET value_expression
+  {
+    $$ = &Limit{Offset: $4, Rowcount: $2}
+  }
+| OFFSET value_expression
+  {
+    $$ = &Limit{Offset: $2}
+  }
+| OFFSET value_expression LIMIT value_expression
+  {
+    $$ = &Limit{Offset: $2, Rowcount: $4}
+  }
 
 lock_opt:
   {
@@ -1044,6 +1070,15 @@ on_dup_opt:
     $$ = $5
   }
 
+returning_opt:
+  {
+    $$ = nil
+  }
+| RETURNING select_expression_list
+  {
+    $$ = $2
+  }
+
 row_list:
   VALUES tuple_list
   {
@@ -1141,12 +1176,54 @@ sql_id:
   {
     $$ = SQLName(strings.ToLower(string($1)))
   }
+| non_reserved_keyword
+
+/*
+  Keywords that can also be used as column and table names.
+  They only act as keywords where an identifier can't appear.
+*/
+non_reserved_keyword:
+  RETURNING
+  {
+    $$ = SQLName("returning")
+  }
+| OFFSET
+  {
+    $$ = SQLName("offset")
+  }
+| non_reserved_alias_keyword
+
+/*
+  Non-reserved keywords that can also be table aliases without AS.
+  OFFSET and RETURNING can't, because they could start the next clause.
+*/
+non_reserved_alias_keyword:
+  NULLS
+  {
+    $$ = SQLName("nulls")
+  }
+| FIRST
+  {
+    $$ = SQLName("first")
+  }
+| LAST
+  {
+    $$ = SQLName("last")
+  }
 
 table_id:
   ID
   {
     $$ = SQLName($1)
   }
+| non_reserved_keyword
+
+table_alias:
+  ID
+  {
+    $$ = SQLName($1)
+  }
+| non_reserved_alias_keyword
 
 openb:
   '('
@@ -68,6 +68,7 @@ var keywords = map[string]int{
 	"exists":         EXISTS,
 	"explain":        EXPLAIN,
 	"false":          FALSE,
+	"first":          FIRST,
 	"for":            FOR,
 	"force":          FORCE,
 	"from":           FROM,
@@ -86,6 +87,7 @@ var keywords = map[string]int{
 	"join":           JOIN,
 	"key":            KEY,
 	"keyrange":       KEYRANGE,
+	"last":           LAST,
 	"last_insert_id": LAST_INSERT_ID,
 	"left":           LEFT,
 	"like":           LIKE,
@@ -95,12 +97,15 @@ var keywords = map[string]int{
 	"natural":        NATURAL,
 	"not":            NOT,
 	"null":           NULL,
+	"nulls":          NULLS,
+	"offset":         OFFSET,
 	"on":             ON,
 	"or":             OR,
 	"order":          ORDER,
 	"outer":          OUTER,
 	"rename":         RENAME,
 	"regexp":         REGEXP,
+	"returning":      RETURNING,
 	"right":          RIGHT,
 	"rlike":          REGEXP,
 	"select":         SELECT,
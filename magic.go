@@ -15,6 +15,10 @@ func transmogrify(v interface{}) interface{} {
 	switch x := v.(type) {
 	case *sqlparser.ColName:
 		name := string(x.Name)
+		if x.Qualifier == "" && strings.HasPrefix(name, "@") {
+			// @name style named parameter
+			return namedArg(name)
+		}
 		if x.Qualifier != "" {
 			name = fmt.Sprintf("%s.%s", x.Qualifier, name)
 		}
@@ -37,17 +41,15 @@ func transmogrify(v interface{}) interface{} {
 		// TODO: figure out some way to make this work
 		return transmogrify(x.Left)
 	case sqlparser.ValArg:
-		// vitess makes args like :v1
+		// vitess makes args like :v1 for ?
 		str := string(x)
-		hdr, num := str[:2], str[2:]
-		if hdr != ":v" {
-			log.Panicln("unexpected arg format", str)
-		}
-		idx, err := strconv.Atoi(num)
-		if err != nil {
-			panic(err)
+		if strings.HasPrefix(str, ":v") {
+			if idx, err := strconv.Atoi(str[2:]); err == nil {
+				return arg(idx - 1)
+			}
 		}
-		return arg(idx - 1)
+		// :name style named parameter
+		return namedArg(str)
 	case sqlparser.StrVal:
 		return string(x)
 	case sqlparser.NumVal:
@@ -103,8 +105,13 @@ func extractBoolExpr(vals map[string]interface{}, expr sqlparser.BoolExpr) map[s
 	case *sqlparser.AndExpr:
 		extractBoolExpr(vals, x.Left)
 		extractBoolExpr(vals, x.Right)
+	case *sqlparser.ParenBoolExpr:
+		extractBoolExpr(vals, x.Expr)
 	case *sqlparser.ComparisonExpr:
-		column := transmogrify(x.Left).(string)
+		column, ok := transmogrify(x.Left).(string)
+		if !ok {
+			break
+		}
 		vals[column] = transmogrify(x.Right)
 	}
 	return vals
@@ -121,9 +128,26 @@ func extractBoolExprWithOps(vals map[colop]interface{}, expr sqlparser.BoolExpr)
 	case *sqlparser.AndExpr:
 		extractBoolExprWithOps(vals, x.Left)
 		extractBoolExprWithOps(vals, x.Right)
+	case *sqlparser.ParenBoolExpr:
+		extractBoolExprWithOps(vals, x.Expr)
 	case *sqlparser.ComparisonExpr:
-		column := transmogrify(x.Left).(string)
+		column, ok := transmogrify(x.Left).(string)
+		if !ok {
+			break
+		}
 		vals[colop{column, x.Operator}] = transmogrify(x.Right)
+	case *sqlparser.RangeCond:
+		column, ok := transmogrify(x.Left).(string)
+		if !ok {
+			break
+		}
+		vals[colop{column, x.Operator}] = []interface{}{transmogrify(x.From), transmogrify(x.To)}
+	case *sqlparser.IsExpr:
+		column, ok := transmogrify(x.Expr).(string)
+		if !ok {
+			break
+		}
+		vals[colop{column, x.Operator}] = nil
 	}
 	return vals
 }
@@ -4,25 +4,30 @@ import (
 	"database/sql"
 	"database/sql/driver"
 	"errors"
-	"fmt"
-	"os"
-	"text/tabwriter"
+	"sync"
+	"testing"
 )
 
 var (
-	// ErrUnstubbed is returned as the result for unstubbed queries.
+	// ErrUnstubbed is returned as the result for unstubbed queries,
+	// wrapped in an *UnstubbedError. Check for it with errors.Is.
 	ErrUnstubbed = errors.New("mogi: query not stubbed")
 	// ErrUnresolved is returned as the result of a stub that was matched,
-	// but whose data could not be resolved. For example, exceeded LIMITs.
+	// but whose data could not be resolved. For example, exceeded LIMITs (see ApplyLimit).
 	ErrUnresolved = errors.New("mogi: query matched but no stub data")
+	// ErrOutOfOrder is returned (wrapped) when a query matches a stub in a Sequence
+	// before the stubs preceding it have matched.
+	ErrOutOfOrder = errors.New("mogi: query out of order")
 
 	// errNotSet is used for Exec results stubbed as -1.
 	errNotSet = errors.New("value set to -1")
 )
 
 var (
+	settingsMu sync.RWMutex
 	verbose    = false
 	timeLayout = ""
+	nullTokens []string
 )
 
 func init() {
@@ -30,74 +35,105 @@ func init() {
 	sql.Register("mogi", drv)
 }
 
-// Reset removes all the stubs that have been set
+// Reset removes all the stubs and tables that have been set, and clears the history.
 func Reset() {
-	drv.conn.stubs = nil
-	drv.conn.execStubs = nil
+	drv.registry("").Reset()
 }
 
 // Verbose turns on unstubbed logging when v is true
 func Verbose(v bool) {
+	settingsMu.Lock()
+	defer settingsMu.Unlock()
 	verbose = v
 }
 
+func isVerbose() bool {
+	settingsMu.RLock()
+	defer settingsMu.RUnlock()
+	return verbose
+}
+
+// SetDialect sets the SQL dialect of queries (e.g. PostgreSQL for $1 placeholders).
+// The default is MySQL. Use New(dsn).SetDialect to set the dialect for other DSNs.
+func SetDialect(d Dialect) {
+	drv.registry("").SetDialect(d)
+}
+
 // ParseTime will configure mogi to convert dates of the given layout
 // (e.g. time.RFC3339) to time.Time when using StubCSV.
 // Give it an empty string to turn off time parsing.
 func ParseTime(layout string) {
+	settingsMu.Lock()
+	defer settingsMu.Unlock()
 	timeLayout = layout
 }
 
+func parseTimeLayout() string {
+	settingsMu.RLock()
+	defer settingsMu.RUnlock()
+	return timeLayout
+}
+
+// ParseNull will configure mogi to convert CSV cells matching one of the given tokens
+// (e.g. "NULL" or `\N`) to nil when using StubCSV.
+// Call it with no tokens to turn off NULL parsing.
+func ParseNull(tokens ...string) {
+	settingsMu.Lock()
+	defer settingsMu.Unlock()
+	nullTokens = tokens
+}
+
+func parseNullTokens() []string {
+	settingsMu.RLock()
+	defer settingsMu.RUnlock()
+	return nullTokens
+}
+
+// ExpectationsWereMet returns an error describing every stub
+// whose call count expectation (Times, AtLeast, etc.) was not satisfied.
+func ExpectationsWereMet() error {
+	return drv.registry("").ExpectationsWereMet()
+}
+
+// History returns a record of every query run since the last Reset, in order.
+func History() []Record {
+	return drv.registry("").History()
+}
+
+// Verify fails t for every stub that was never matched,
+// every stub whose call count expectation was not satisfied,
+// and every query that returned ErrUnstubbed or ErrOutOfOrder.
+func Verify(t testing.TB) {
+	t.Helper()
+	drv.registry("").Verify(t)
+}
+
+// Cleanup registers a function with t.Cleanup that verifies and then resets all stubs
+// when the test finishes. It replaces the usual defer mogi.Reset().
+func Cleanup(t testing.TB) {
+	drv.registry("").Cleanup(t)
+}
+
 // Dump prints all the current stubs, in order of priority.
 // Helpful for debugging.
 func Dump() {
-	w := tabwriter.NewWriter(os.Stdout, 0, 8, 0, '\t', 0)
-	fmt.Fprintf(w, ">>\t\tQuery stubs: (%d total)\t\n", len(drv.conn.stubs))
-	fmt.Fprintf(w, "\t\t=========================\t\n")
-	for rank, s := range drv.conn.stubs {
-		for i, c := range s.chain {
-			if i == 0 {
-				fmt.Fprintf(w, "#%d\t[%d]\t%s\t[%+d]\n", rank+1, s.priority(), c, c.priority())
-				continue
-			}
-			fmt.Fprintf(w, "\t\t%s\t[%+d]\n", c, c.priority())
-		}
-		switch {
-		case s.err != nil:
-			fmt.Fprintf(w, "\t\t→ error: %v\t\n", s.err)
-		case s.data != nil, s.resolve != nil:
-			fmt.Fprintf(w, "\t\t→ data\t\n")
-		}
-	}
-	fmt.Fprintf(w, "\t\t\t\n")
-	fmt.Fprintf(w, ">>\t\tExec stubs: (%d total)\t\n", len(drv.conn.execStubs))
-	fmt.Fprintf(w, "\t\t=========================\t\n")
-	for rank, s := range drv.conn.execStubs {
-		for i, c := range s.chain {
-			if i == 0 {
-				fmt.Fprintf(w, "#%d\t[%d]\t%s\t[%+d]\n", rank+1, s.priority(), c, c.priority())
-				continue
-			}
-			fmt.Fprintf(w, "\t\t%s\t[%+d]\n", c, c.priority())
-		}
-		switch {
-		case s.err != nil:
-			fmt.Fprintf(w, "\t\t→ error: %v\t\n", s.err)
-		case s.result != nil:
-			if r, ok := s.result.(execResult); ok {
-				fmt.Fprintf(w, "\t\t→ result ID: %d, rows: %d\t\n", r.lastInsertID, r.rowsAffected)
-			} else {
-				fmt.Fprintf(w, "\t\t→ result %T\t\n", s.result)
-			}
-		}
-	}
-	w.Flush()
+	drv.registry("").Dump()
 }
 
-// func Replace() {
-// 	drv.conn = newConn()
-// }
-
 var _ driver.Stmt = &stmt{}
+var _ driver.StmtQueryContext = &stmt{}
+var _ driver.StmtExecContext = &stmt{}
 var _ driver.Conn = &conn{}
+var _ driver.QueryerContext = &conn{}
+var _ driver.ExecerContext = &conn{}
+var _ driver.ConnPrepareContext = &conn{}
+var _ driver.ConnBeginTx = &conn{}
+var _ driver.Pinger = &conn{}
+var _ driver.NamedValueChecker = &conn{}
 var _ driver.Driver = &mdriver{}
+var _ driver.RowsColumnTypeDatabaseTypeName = &rows{}
+var _ driver.RowsColumnTypeScanType = &rows{}
+var _ driver.RowsColumnTypeNullable = &rows{}
+var _ driver.RowsColumnTypeLength = &rows{}
+var _ driver.RowsColumnTypePrecisionScale = &rows{}
+var _ driver.RowsNextResultSet = &rows{}
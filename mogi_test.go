@@ -3,6 +3,7 @@ package mogi_test
 import (
 	"database/sql"
 	"database/sql/driver"
+	"errors"
 	"testing"
 
 	"github.com/guregu/mogi"
@@ -26,7 +27,7 @@ func TestMogi(t *testing.T) {
 	// test reset
 	mogi.Reset()
 	_, err := db.Query("SELECT id, name, brewery, pct FROM beer WHERE pct > ?", 5)
-	if err != mogi.ErrUnstubbed {
+	if !errors.Is(err, mogi.ErrUnstubbed) {
 		t.Error("after reset, err should be ErrUnstubbed but is", err)
 	}
 
@@ -59,6 +60,17 @@ func checkNil(t *testing.T, err error) {
 	}
 }
 
+func checkRowsAffected(t *testing.T, res sql.Result, expect int64) {
+	if res == nil {
+		return
+	}
+	n, err := res.RowsAffected()
+	checkNil(t, err)
+	if n != expect {
+		t.Error("rows affected should be", expect, "but is", n)
+	}
+}
+
 func openDB() *sql.DB {
 	db, _ := sql.Open("mogi", "")
 	return db
@@ -0,0 +1,60 @@
+package mogi_test
+
+import (
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/guregu/mogi"
+)
+
+func TestNamedArgs(t *testing.T) {
+	defer mogi.Reset()
+	db := openDB()
+
+	// :name
+	mogi.Select().From("beer").Where("id", 10).StubCSV(`10,Apex,Bear Republic Brewing Co.,8.95`)
+	_, err := db.Query("SELECT id, name, brewery, pct FROM beer WHERE id = :id", sql.Named("id", 10))
+	checkNil(t, err)
+
+	// @name
+	mogi.Reset()
+	mogi.Select().From("beer").Where("id", 10).Where("brewery", "Bear Republic Brewing Co.").StubCSV(`10,Apex,Bear Republic Brewing Co.,8.95`)
+	_, err = db.Query("SELECT id, name, brewery, pct FROM beer WHERE id = @id AND brewery = @brewery",
+		sql.Named("brewery", "Bear Republic Brewing Co."), sql.Named("id", 10))
+	checkNil(t, err)
+
+	// ArgNamed
+	mogi.Reset()
+	mogi.Select().ArgNamed("id", 10).StubCSV(`10,Apex,Bear Republic Brewing Co.,8.95`)
+	_, err = db.Query("SELECT id, name, brewery, pct FROM beer WHERE id = :id", sql.Named("id", 10))
+	checkNil(t, err)
+	_, err = db.Query("SELECT id, name, brewery, pct FROM beer WHERE id = :id", sql.Named("id", 11))
+	if !errors.Is(err, mogi.ErrUnstubbed) {
+		t.Error("err should be ErrUnstubbed but is", err)
+	}
+	_, err = db.Query("SELECT id, name, brewery, pct FROM beer WHERE id = ?", 10)
+	if !errors.Is(err, mogi.ErrUnstubbed) {
+		t.Error("err should be ErrUnstubbed but is", err)
+	}
+}
+
+func TestNamedArgsExec(t *testing.T) {
+	defer mogi.Reset()
+	db := openDB()
+
+	mogi.Insert().Into("beer").Value("name", "Mikkel’s Dream").Value("pct", 4.6).ArgNamed("brewery", "Mikkeller").StubResult(3, 1)
+	_, err := db.Exec("INSERT INTO beer (name, brewery, pct) VALUES (:name, :brewery, @pct)",
+		sql.Named("name", "Mikkel’s Dream"), sql.Named("brewery", "Mikkeller"), sql.Named("pct", 4.6))
+	checkNil(t, err)
+
+	mogi.Update().Table("beer").Value("pct", 4.7).Where("id", 3).StubRowsAffected(1)
+	_, err = db.Exec("UPDATE beer SET pct = :pct WHERE id = :id", sql.Named("id", 3), sql.Named("pct", 4.7))
+	checkNil(t, err)
+
+	history := mogi.History()
+	if len(history) != 2 {
+		t.Fatal("history should have 2 records, but has", len(history))
+	}
+	checkRecord(t, history[1].NamedArgs["id"], int64(3))
+}
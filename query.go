@@ -0,0 +1,97 @@
+package mogi
+
+import (
+	"database/sql/driver"
+)
+
+// Query is a read-only view of a query, given to custom matchers added with Match.
+type Query struct {
+	in *input
+}
+
+// SQL returns the raw query.
+func (q Query) SQL() string {
+	return q.in.query
+}
+
+// Args returns the args passed to the query.
+func (q Query) Args() []driver.Value {
+	return append([]driver.Value(nil), q.in.args...)
+}
+
+// NamedArgs returns the named args (sql.Named) passed to the query, by name.
+func (q Query) NamedArgs() map[string]driver.Value {
+	named := make(map[string]driver.Value, len(q.in.named))
+	for k, v := range q.in.named {
+		named[k] = v
+	}
+	return named
+}
+
+// Kind returns the type of statement: SELECT, UNION, INSERT, UPDATE, DELETE, SET, DDL, or OTHER.
+func (q Query) Kind() string {
+	return q.in.kind()
+}
+
+// Cols returns the columns selected, inserted, or updated.
+func (q Query) Cols() []string {
+	return q.in.cols()
+}
+
+// Tables returns the (un-aliased) tables used by the query.
+func (q Query) Tables() []string {
+	return q.in.tables()
+}
+
+// Where returns the values in the WHERE clause by column, for SELECT, UPDATE, and DELETE.
+// Placeholders are replaced by the query's args.
+func (q Query) Where() map[string]interface{} {
+	where := q.in.where()
+	if where == nil {
+		return nil
+	}
+	cp := make(map[string]interface{}, len(where))
+	for k, v := range where {
+		cp[k] = v
+	}
+	return cp
+}
+
+// Rows returns the values inserted by column, for INSERTs.
+func (q Query) Rows() []map[string]interface{} {
+	return q.in.rows()
+}
+
+// Values returns the values of the SET clause by column, for UPDATEs.
+func (q Query) Values() map[string]interface{} {
+	return q.in.values()
+}
+
+// InTx returns true if the query was run in a transaction.
+func (q Query) InTx() bool {
+	return q.in.tx != nil
+}
+
+// Satisfies returns true if the query's WHERE clause would be satisfied by the given row,
+// whose keys are column names. Queries using a column missing from the row aren't satisfied.
+func (q Query) Satisfies(row map[string]interface{}) bool {
+	wc := newWhereRowCond(row)
+	return wc.matches(q.in)
+}
+
+type matchCond struct {
+	fn func(Query) bool
+	p  int
+}
+
+func (mc matchCond) matches(in *input) bool {
+	return mc.fn(Query{in: in})
+}
+
+func (mc matchCond) priority() int {
+	return mc.p
+}
+
+func (mc matchCond) String() string {
+	return "MATCH (custom)"
+}
@@ -0,0 +1,132 @@
+package mogi_test
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/guregu/mogi"
+)
+
+func TestMatch(t *testing.T) {
+	defer mogi.Reset()
+	db := openDB()
+
+	var got mogi.Query
+	mogi.Select().From("beer").Match(func(q mogi.Query) bool {
+		got = q
+		return strings.HasPrefix(q.Where()["name"].(string), "Yona")
+	}, 2).StubCSV(beerCSV)
+
+	_, err := db.Query("SELECT id, name FROM beer WHERE name = ? AND brewery = :brewery", "Yona Yona Ale", sql.Named("brewery", "Yo-Ho Brewing"))
+	checkNil(t, err)
+	if got.Kind() != "SELECT" || got.SQL() != "SELECT id, name FROM beer WHERE name = ? AND brewery = :brewery" || got.InTx() {
+		t.Error("bad query:", got.Kind(), got.SQL(), got.InTx())
+	}
+	if len(got.Args()) != 2 || got.NamedArgs()["brewery"] != "Yo-Ho Brewing" {
+		t.Error("bad args:", got.Args(), got.NamedArgs())
+	}
+	if cols := got.Cols(); len(cols) != 2 || cols[1] != "name" {
+		t.Error("bad cols:", cols)
+	}
+	if !got.Satisfies(map[string]interface{}{"name": "Yona Yona Ale", "brewery": "Yo-Ho Brewing"}) {
+		t.Error("query should be satisfied")
+	}
+
+	_, err = db.Query("SELECT id, name FROM beer WHERE name = ?", "Punk IPA")
+	if !errors.Is(err, mogi.ErrUnstubbed) {
+		t.Error("err should be ErrUnstubbed but is", err)
+	}
+
+	// priority
+	mogi.Reset()
+	mogi.Insert().Into("beer").StubResult(1, 1)
+	mogi.Insert().Match(func(q mogi.Query) bool {
+		return len(q.Rows()) == 2
+	}, 10).StubResult(2, 2)
+	res, err := db.Exec("INSERT INTO beer (name) VALUES (?), (?)", "Yona Yona Ale", "Punk IPA")
+	checkNil(t, err)
+	checkRowsAffected(t, res, 2)
+}
+
+func TestStubFunc(t *testing.T) {
+	defer mogi.Reset()
+	db := openDB()
+
+	// echo back the requested IDs
+	mogi.Select("id", "name").From("beer").StubFunc(func(q mogi.Query) ([][]driver.Value, error) {
+		ids, ok := q.Where()["id"].([]interface{})
+		if !ok {
+			return nil, errors.New("no ids")
+		}
+		var rows [][]driver.Value
+		for _, id := range ids {
+			rows = append(rows, []driver.Value{id, fmt.Sprintf("beer #%v", id)})
+		}
+		return rows, nil
+	})
+	rows, err := db.Query("SELECT id, name FROM beer WHERE id IN (?, ?, ?)", 3, 1, 4)
+	checkNil(t, err)
+	var names []string
+	for rows.Next() {
+		var id int
+		var name string
+		checkNil(t, rows.Scan(&id, &name))
+		names = append(names, name)
+	}
+	if strings.Join(names, ", ") != "beer #3, beer #1, beer #4" {
+		t.Error("bad names:", names)
+	}
+	_, err = db.Query("SELECT id, name FROM beer WHERE id = ?", 3)
+	if err == nil || err.Error() != "no ids" {
+		t.Error("err should be no ids but is", err)
+	}
+
+	// LastInsertId from the inserted row
+	mogi.Insert("id", "name").Into("beer").StubFunc(func(q mogi.Query) (driver.Result, error) {
+		return sqlResult{id: q.Rows()[0]["id"].(int64), rows: int64(len(q.Rows()))}, nil
+	})
+	res, err := db.Exec("INSERT INTO beer (id, name) VALUES (?, ?)", 42, "Westvleteren XII")
+	checkNil(t, err)
+	checkRowsAffected(t, res, 1)
+	id, err := res.LastInsertId()
+	checkNil(t, err)
+	if id != 42 {
+		t.Error("LastInsertId should be 42 but is", id)
+	}
+}
+
+type sqlResult struct {
+	id, rows int64
+}
+
+func (r sqlResult) LastInsertId() (int64, error) { return r.id, nil }
+func (r sqlResult) RowsAffected() (int64, error) { return r.rows, nil }
+
+func TestMatchCallback(t *testing.T) {
+	defer mogi.Reset()
+	db := openDB()
+
+	// calling back into mogi from a matcher shouldn't deadlock
+	mogi.Select().From("beer").Match(func(q mogi.Query) bool {
+		return len(mogi.History()) > 100
+	}, 1).StubCSV(beerCSV)
+
+	done := make(chan error)
+	go func() {
+		_, err := db.Query("SELECT id, name, brewery, pct FROM beer")
+		done <- err
+	}()
+	select {
+	case err := <-done:
+		if !errors.Is(err, mogi.ErrUnstubbed) {
+			t.Error("err should be ErrUnstubbed but is", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("deadlocked")
+	}
+}
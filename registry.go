@@ -0,0 +1,434 @@
+package mogi
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"text/tabwriter"
+)
+
+// Registry is a set of stubs belonging to a single DSN.
+// Connections opened with sql.Open("mogi", dsn) only see the stubs
+// of the registry for that DSN, so tests using different DSNs
+// (e.g. with t.Parallel()) won't clobber each other's stubs.
+// A Registry is safe for concurrent use.
+type Registry struct {
+	dsn string
+
+	mu        sync.RWMutex
+	dialect   Dialect
+	stubs     stubs
+	execStubs execStubs
+	tables    map[string]*TableStub // by lowercase name
+	failures  []string              // unstubbed or out of order queries
+	history   []Record
+}
+
+func newRegistry(dsn string) *Registry {
+	return &Registry{
+		dsn: dsn,
+	}
+}
+
+// New returns the registry for the given DSN, creating it if necessary.
+// Use it with a database opened by sql.Open("mogi", dsn).
+// The package-level functions (Select, Insert, Reset, etc.) use the registry for the empty DSN.
+func New(dsn string) *Registry {
+	return drv.registry(dsn)
+}
+
+// DSN returns the data source name this registry is bound to.
+func (r *Registry) DSN() string {
+	return r.dsn
+}
+
+// SetDialect sets the SQL dialect of queries run against this registry.
+// The default is MySQL.
+func (r *Registry) SetDialect(d Dialect) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.dialect = d
+}
+
+// Dialect returns the SQL dialect of queries run against this registry.
+func (r *Registry) Dialect() Dialect {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.dialect
+}
+
+// Select starts a new stub for SELECT statements in this registry.
+// See the package-level Select.
+func (r *Registry) Select(cols ...string) *Stub {
+	return newStub(r, cols)
+}
+
+// Insert starts a new stub for INSERT statements in this registry.
+// See the package-level Insert.
+func (r *Registry) Insert(cols ...string) *ExecStub {
+	return newExecStub(r, insertCond{
+		cols: cols,
+	})
+}
+
+// Update starts a new stub for UPDATE statements in this registry.
+// See the package-level Update.
+func (r *Registry) Update(cols ...string) *ExecStub {
+	return newExecStub(r, updateCond{
+		cols: cols,
+	})
+}
+
+// Delete starts a new stub for DELETE statements in this registry.
+func (r *Registry) Delete() *ExecStub {
+	return newExecStub(r, deleteCond{})
+}
+
+// Reset removes all the stubs and tables that have been set in this registry, and clears its history.
+func (r *Registry) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.stubs = nil
+	r.execStubs = nil
+	r.tables = nil
+	r.failures = nil
+	r.history = nil
+}
+
+// Table declares an in-memory table for this registry. See the package-level Table.
+func (r *Registry) Table(name string, cols ...string) *TableStub {
+	t := newTableStub(name, cols)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.tables == nil {
+		r.tables = make(map[string]*TableStub)
+	}
+	r.tables[strings.ToLower(name)] = t
+	return t
+}
+
+// table returns the declared table used by in, or nil.
+func (r *Registry) table(in *input) *TableStub {
+	switch in.kind() {
+	case "SELECT", "INSERT", "UPDATE", "DELETE":
+	default:
+		return nil
+	}
+	tables := in.tables()
+	if len(tables) == 0 {
+		return nil
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.tables[strings.ToLower(tables[0])]
+}
+
+func (r *Registry) addStub(s *Stub) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.stubs = append(r.stubs, s)
+	sort.Sort(r.stubs)
+}
+
+func (r *Registry) addExecStub(s *ExecStub) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.execStubs = append(r.execStubs, s)
+	sort.Sort(r.execStubs)
+}
+
+// matchStub returns the highest priority query stub matching the given input, or nil.
+// If the only matches are out of order, it returns an error wrapping ErrOutOfOrder.
+// It also returns an error if it would have matched a stub that is invalid, such as one with a malformed WhereExpr.
+func (r *Registry) matchStub(in *input) (*Stub, error) {
+	r.mu.RLock()
+	candidates := make(stubs, len(r.stubs))
+	copy(candidates, r.stubs)
+	r.mu.RUnlock()
+
+	var outOfOrder error
+	for _, s := range candidates {
+		ok, err := r.try(s.chain, &s.count, s.seq, s.seqPos, in)
+		switch {
+		case errors.Is(err, ErrOutOfOrder):
+			if outOfOrder == nil {
+				outOfOrder = err
+			}
+			continue
+		case err != nil:
+			return nil, r.fail(err)
+		}
+		if ok {
+			return s, nil
+		}
+	}
+	return nil, r.fail(outOfOrder)
+}
+
+// matchExecStub returns the highest priority exec stub matching the given input, or nil.
+// If the only matches are out of order, it returns an error wrapping ErrOutOfOrder.
+// It also returns an error if it would have matched a stub that is invalid, such as one with a malformed WhereExpr.
+func (r *Registry) matchExecStub(in *input) (*ExecStub, error) {
+	r.mu.RLock()
+	candidates := make(execStubs, len(r.execStubs))
+	copy(candidates, r.execStubs)
+	r.mu.RUnlock()
+
+	var outOfOrder error
+	for _, s := range candidates {
+		ok, err := r.try(s.chain, &s.count, s.seq, s.seqPos, in)
+		switch {
+		case errors.Is(err, ErrOutOfOrder):
+			if outOfOrder == nil {
+				outOfOrder = err
+			}
+			continue
+		case err != nil:
+			return nil, r.fail(err)
+		}
+		if ok {
+			return s, nil
+		}
+	}
+	return nil, r.fail(outOfOrder)
+}
+
+// try matches a stub against the input and takes a call from its count, returning true if it matched.
+// Conds can run user code (Match), so they run without holding the lock.
+func (r *Registry) try(chain condchain, count *expectation, seq *Sequence, seqPos int, in *input) (bool, error) {
+	r.mu.RLock()
+	usedUp, exact := count.usedUp(), count.exact()
+	r.mu.RUnlock()
+	if usedUp {
+		if exact && chain.matchesQuietly(in) {
+			r.mu.Lock()
+			count.excess++
+			r.mu.Unlock()
+		}
+		return false, nil
+	}
+	if !chain.matches(in) {
+		return false, chain.invalid(in)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if count.usedUp() {
+		// another query got here first
+		return false, nil
+	}
+	if seq != nil {
+		if err := seq.advance(seqPos, in); err != nil {
+			return false, err
+		}
+	}
+	count.take()
+	return true, nil
+}
+
+// fail records err, if any, as a failure.
+func (r *Registry) fail(err error) error {
+	if err == nil {
+		return nil
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.failures = append(r.failures, err.Error())
+	return err
+}
+
+// unstubbed records an unstubbed query and returns an error explaining why the query stubs didn't match.
+func (r *Registry) unstubbed(in *input) *UnstubbedError {
+	r.mu.Lock()
+	chains := make([]condchain, len(r.stubs))
+	counts := make([]expectation, len(r.stubs))
+	for i, s := range r.stubs {
+		chains[i] = s.chain
+		counts[i] = s.count
+	}
+	r.mu.Unlock()
+
+	// conds can run user code (Match), so diagnose without holding the lock
+	err := diagnose(in, chains, counts)
+	r.mu.Lock()
+	r.failures = append(r.failures, err.Error())
+	r.mu.Unlock()
+	return err
+}
+
+// execUnstubbed records an unstubbed query and returns an error explaining why the exec stubs didn't match.
+func (r *Registry) execUnstubbed(in *input) *UnstubbedError {
+	r.mu.Lock()
+	chains := make([]condchain, len(r.execStubs))
+	counts := make([]expectation, len(r.execStubs))
+	for i, s := range r.execStubs {
+		chains[i] = s.chain
+		counts[i] = s.count
+	}
+	r.mu.Unlock()
+
+	// conds can run user code (Match), so diagnose without holding the lock
+	err := diagnose(in, chains, counts)
+	r.mu.Lock()
+	r.failures = append(r.failures, err.Error())
+	r.mu.Unlock()
+	return err
+}
+
+func (r *Registry) addRecord(rec Record) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.history = append(r.history, rec)
+}
+
+// History returns a record of every query run against this registry since it was last reset, in order.
+func (r *Registry) History() []Record {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	history := make([]Record, len(r.history))
+	for i, rec := range r.history {
+		history[i] = rec.withQuery()
+	}
+	return history
+}
+
+// ExpectationsWereMet returns an error describing every stub in this registry
+// whose call count expectation (Times, AtLeast, etc.) was not satisfied.
+func (r *Registry) ExpectationsWereMet() error {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var problems []string
+	for _, s := range r.stubs {
+		if !s.count.met() {
+			problems = append(problems, fmt.Sprintf("%s: %s", s.chain, s.count.problem()))
+		}
+	}
+	for _, s := range r.execStubs {
+		if !s.count.met() {
+			problems = append(problems, fmt.Sprintf("%s: %s", s.chain, s.count.problem()))
+		}
+	}
+	if len(problems) == 0 {
+		return nil
+	}
+	return fmt.Errorf("mogi: unmet expectations:\n\t%s", strings.Join(problems, "\n\t"))
+}
+
+// Verify fails t for every stub in this registry that was never matched,
+// every stub whose call count expectation was not satisfied,
+// and every query that returned ErrUnstubbed or ErrOutOfOrder.
+func (r *Registry) Verify(t testing.TB) {
+	t.Helper()
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	for _, s := range r.stubs {
+		switch {
+		case !s.count.met():
+			t.Errorf("mogi: %s: %s", s.chain, s.count.problem())
+		case s.count.unused():
+			t.Errorf("mogi: unused stub: %s", s.chain)
+		}
+	}
+	for _, s := range r.execStubs {
+		switch {
+		case !s.count.met():
+			t.Errorf("mogi: %s: %s", s.chain, s.count.problem())
+		case s.count.unused():
+			t.Errorf("mogi: unused stub: %s", s.chain)
+		}
+	}
+	for _, msg := range r.failures {
+		t.Errorf("%s", msg)
+	}
+}
+
+// Cleanup registers a function with t.Cleanup that verifies and then resets this registry
+// when the test finishes. It replaces the usual defer Reset().
+func (r *Registry) Cleanup(t testing.TB) {
+	t.Cleanup(func() {
+		t.Helper()
+		r.Verify(t)
+		r.Reset()
+	})
+}
+
+// Dump prints all the current stubs of this registry, in order of priority.
+// Helpful for debugging.
+func (r *Registry) Dump() {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	w := tabwriter.NewWriter(os.Stdout, 0, 8, 0, '\t', 0)
+	fmt.Fprintf(w, ">>\t\tQuery stubs: (%d total)\t\n", len(r.stubs))
+	fmt.Fprintf(w, "\t\t=========================\t\n")
+	for rank, s := range r.stubs {
+		for i, c := range s.chain {
+			if i == 0 {
+				fmt.Fprintf(w, "#%d\t[%d]\t%s\t[%+d]\n", rank+1, s.priority(), c, c.priority())
+				continue
+			}
+			fmt.Fprintf(w, "\t\t%s\t[%+d]\n", c, c.priority())
+		}
+		if s.count.limited() {
+			fmt.Fprintf(w, "\t\t× %s (matched %d)\t\n", s.count, s.count.calls)
+		}
+		switch {
+		case s.err != nil:
+			fmt.Fprintf(w, "\t\t→ error: %v\t\n", s.err)
+		case s.sets != nil:
+			fmt.Fprintf(w, "\t\t→ %d result sets\t\n", len(s.sets))
+		case s.data != nil, s.resolve != nil:
+			fmt.Fprintf(w, "\t\t→ data\t\n")
+		}
+		s.fail.dump(w)
+	}
+	fmt.Fprintf(w, "\t\t\t\n")
+	fmt.Fprintf(w, ">>\t\tExec stubs: (%d total)\t\n", len(r.execStubs))
+	fmt.Fprintf(w, "\t\t=========================\t\n")
+	for rank, s := range r.execStubs {
+		for i, c := range s.chain {
+			if i == 0 {
+				fmt.Fprintf(w, "#%d\t[%d]\t%s\t[%+d]\n", rank+1, s.priority(), c, c.priority())
+				continue
+			}
+			fmt.Fprintf(w, "\t\t%s\t[%+d]\n", c, c.priority())
+		}
+		if s.count.limited() {
+			fmt.Fprintf(w, "\t\t× %s (matched %d)\t\n", s.count, s.count.calls)
+		}
+		switch {
+		case s.err != nil:
+			fmt.Fprintf(w, "\t\t→ error: %v\t\n", s.err)
+		case s.result != nil:
+			if r, ok := s.result.(execResult); ok {
+				fmt.Fprintf(w, "\t\t→ result ID: %d, rows: %d\t\n", r.lastInsertID, r.rowsAffected)
+			} else {
+				fmt.Fprintf(w, "\t\t→ result %T\t\n", s.result)
+			}
+		case s.resultFunc != nil:
+			fmt.Fprintf(w, "\t\t→ func\t\n")
+		case s.data != nil, s.resolve != nil:
+			fmt.Fprintf(w, "\t\t→ data\t\n")
+		}
+		s.fail.dump(w)
+	}
+	if len(r.tables) > 0 {
+		names := make([]string, 0, len(r.tables))
+		for name := range r.tables {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		fmt.Fprintf(w, "\t\t\t\n")
+		fmt.Fprintf(w, ">>\t\tTables: (%d total)\t\n", len(r.tables))
+		fmt.Fprintf(w, "\t\t=========================\t\n")
+		for _, name := range names {
+			t := r.tables[name]
+			fmt.Fprintf(w, "\t\t%s (%s)\t\n", t, strings.Join(t.cols, ", "))
+			fmt.Fprintf(w, "\t\t→ %d rows\t\n", len(t.Rows()))
+		}
+	}
+	w.Flush()
+}
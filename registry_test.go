@@ -0,0 +1,61 @@
+package mogi_test
+
+import (
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/guregu/mogi"
+)
+
+func TestRegistryIsolation(t *testing.T) {
+	a := mogi.New("suite-a")
+	b := mogi.New("suite-b")
+	defer a.Reset()
+	defer b.Reset()
+
+	if mogi.New("suite-a") != a {
+		t.Error("New should return the same registry for the same DSN")
+	}
+
+	dbA, _ := sql.Open("mogi", "suite-a")
+	dbB, _ := sql.Open("mogi", "suite-b")
+
+	a.Select().StubCSV(beerCSV)
+	runBeerSelectQuery(t, dbA)
+	runUnstubbedSelect(t, dbB)
+	runUnstubbedSelect(t, openDB())
+
+	b.Insert().Into("beer").StubResult(3, 1)
+	_, err := dbB.Exec("INSERT INTO beer (name, brewery, pct) VALUES (?, ?, ?)", "Mikkel’s Dream", "Mikkeller", 4.6)
+	checkNil(t, err)
+	_, err = dbA.Exec("INSERT INTO beer (name, brewery, pct) VALUES (?, ?, ?)", "Mikkel’s Dream", "Mikkeller", 4.6)
+	if !errors.Is(err, mogi.ErrUnstubbed) {
+		t.Error("err should be ErrUnstubbed but is", err)
+	}
+
+	// resetting one registry leaves the other alone
+	b.Reset()
+	runBeerSelectQuery(t, dbA)
+	_, err = dbB.Exec("INSERT INTO beer (name, brewery, pct) VALUES (?, ?, ?)", "Mikkel’s Dream", "Mikkeller", 4.6)
+	if !errors.Is(err, mogi.ErrUnstubbed) {
+		t.Error("after reset, err should be ErrUnstubbed but is", err)
+	}
+}
+
+func TestRegistryParallel(t *testing.T) {
+	for _, dsn := range []string{"parallel-1", "parallel-2", "parallel-3"} {
+		dsn := dsn
+		t.Run(dsn, func(t *testing.T) {
+			t.Parallel()
+			reg := mogi.New(dsn)
+			defer reg.Reset()
+			db, _ := sql.Open("mogi", dsn)
+
+			reg.Select().Where("pct", 5).StubCSV(beerCSV)
+			runBeerSelectQuery(t, db)
+			reg.Reset()
+			runUnstubbedSelect(t, db)
+		})
+	}
+}
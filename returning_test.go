@@ -0,0 +1,86 @@
+package mogi_test
+
+import (
+	"database/sql/driver"
+	"errors"
+	"testing"
+
+	"github.com/guregu/mogi"
+)
+
+func TestInsertReturning(t *testing.T) {
+	defer mogi.Reset()
+	db := openDB()
+
+	mogi.Insert("name", "brewery", "pct").Into("beer").StubCSV(`4,Yona Yona Ale`)
+	var id int64
+	var name string
+	err := db.QueryRow("INSERT INTO beer (name, brewery, pct) VALUES (?, ?, ?) RETURNING id, name",
+		"Yona Yona Ale", "Yo-Ho Brewing", 5.5).Scan(&id, &name)
+	checkNil(t, err)
+	if id != 4 || name != "Yona Yona Ale" {
+		t.Error("bad RETURNING values:", id, name)
+	}
+
+	// same stub through Exec
+	res, err := db.Exec("INSERT INTO beer (name, brewery, pct) VALUES (?, ?, ?) RETURNING id",
+		"Yona Yona Ale", "Yo-Ho Brewing", 5.5)
+	checkNil(t, err)
+	checkRowsAffected(t, res, 1)
+
+	// SELECT stubs don't match
+	mogi.Reset()
+	mogi.Select().StubCSV(`4`)
+	_, err = db.Query("INSERT INTO beer (name) VALUES (?) RETURNING id", "Yona Yona Ale")
+	if !errors.Is(err, mogi.ErrUnstubbed) {
+		t.Error("err should be ErrUnstubbed but is", err)
+	}
+}
+
+func TestUpdateReturning(t *testing.T) {
+	defer mogi.Reset()
+	db := openDB()
+
+	mogi.Update("pct").Table("beer").Where("brewery", "Mikkeller").StubRows([][]driver.Value{
+		{3, 4.7},
+		{5, 9.1},
+	})
+	rows, err := db.Query("UPDATE beer SET pct = pct + 0.1 WHERE brewery = ? RETURNING id, pct", "Mikkeller")
+	checkNil(t, err)
+	defer rows.Close()
+	cols, err := rows.Columns()
+	checkNil(t, err)
+	if len(cols) != 2 || cols[0] != "id" || cols[1] != "pct" {
+		t.Error("bad columns:", cols)
+	}
+	var n int
+	for rows.Next() {
+		n++
+	}
+	checkNil(t, rows.Err())
+	if n != 2 {
+		t.Error("expected 2 rows, got", n)
+	}
+}
+
+func TestDeleteReturning(t *testing.T) {
+	defer mogi.Reset()
+	db := openDB()
+
+	// stubs without data return no rows
+	mogi.Delete().Table("beer").StubRowsAffected(1)
+	rows, err := db.Query("DELETE FROM beer WHERE id = ? RETURNING *", 3)
+	checkNil(t, err)
+	if rows.Next() {
+		t.Error("expected no rows")
+	}
+	rows.Close()
+
+	mogi.Reset()
+	errDeleted := errors.New("already deleted")
+	mogi.Delete().Table("beer").StubError(errDeleted)
+	_, err = db.Query("DELETE FROM beer WHERE id = ? RETURNING *", 3)
+	if err != errDeleted {
+		t.Error("err should be errDeleted but is", err)
+	}
+}
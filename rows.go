@@ -3,14 +3,22 @@ package mogi
 import (
 	"database/sql/driver"
 	"encoding/csv"
+	"fmt"
 	"io"
+	"reflect"
+	"strconv"
 	"strings"
 	"time"
 )
 
 type rows struct {
-	cols []string
-	data [][]driver.Value
+	cols  []string
+	data  [][]driver.Value
+	types map[string]columnType // declared types, by lowercase column name
+	next  []resultSet           // result sets after this one
+	fail  rowErrors
+	read  int // rows read, across all result sets
+	err   error
 
 	cursor int
 	closed bool
@@ -23,6 +31,64 @@ func newRows(cols []string, data [][]driver.Value) *rows {
 	}
 }
 
+// rowErrors simulates errors while reading rows.
+type rowErrors struct {
+	after    int // rows read before failing with err
+	err      error
+	closeErr error
+}
+
+func (re rowErrors) dump(w io.Writer) {
+	if re.err != nil {
+		fmt.Fprintf(w, "\t\t→ error after %d rows: %v\t\n", re.after, re.err)
+	}
+	if re.closeErr != nil {
+		fmt.Fprintf(w, "\t\t→ error on close: %v\t\n", re.closeErr)
+	}
+}
+
+// ResultSet is one of the result sets given to StubResultSets.
+type ResultSet struct {
+	// Columns are the names of the columns.
+	// If empty, the columns of the query are used.
+	Columns []string
+	// Rows are the rows of data.
+	Rows [][]driver.Value
+	// CSV is used for the rows instead if not empty.
+	// It is converted like StubCSV, respecting TypeHint and ParseNull.
+	CSV string
+}
+
+type resultSet struct {
+	cols []string
+	data [][]driver.Value
+}
+
+// resultSetRows returns rows starting at the first set, with the rest queued up behind it.
+func resultSetRows(cols []string, sets []ResultSet, hints map[string]CSVType) (*rows, error) {
+	rs := make([]resultSet, 0, len(sets))
+	for _, set := range sets {
+		setCols := set.Columns
+		if len(setCols) == 0 {
+			setCols = cols
+		}
+		data := set.Rows
+		if set.CSV != "" {
+			var err error
+			if data, err = csvToValues(setCols, set.CSV, hints); err != nil {
+				return nil, err
+			}
+		}
+		rs = append(rs, resultSet{cols: setCols, data: data})
+	}
+	if len(rs) == 0 {
+		return newRows(cols, nil), nil
+	}
+	r := newRows(rs[0].cols, rs[0].data)
+	r.next = rs[1:]
+	return r, nil
+}
+
 func (r *rows) Columns() []string {
 	return r.cols
 }
@@ -30,14 +96,74 @@ func (r *rows) Columns() []string {
 // Close closes the rows iterator.
 func (r *rows) Close() error {
 	r.closed = true
-	return nil
+	return r.fail.closeErr
 }
 
 func (r *rows) Err() error {
+	return r.err
+}
+
+// HasNextResultSet reports whether there is another result set after this one.
+func (r *rows) HasNextResultSet() bool {
+	return len(r.next) > 0
+}
+
+// NextResultSet advances to the next result set.
+func (r *rows) NextResultSet() error {
+	if len(r.next) == 0 {
+		return io.EOF
+	}
+	r.cols, r.data = r.next[0].cols, r.next[0].data
+	r.next = r.next[1:]
+	r.cursor = 0
+	r.closed = false
 	return nil
 }
 
+func (r *rows) columnType(i int) columnType {
+	if ct, ok := r.types[strings.ToLower(r.cols[i])]; ok {
+		return ct
+	}
+	return inferColumnType(r.data, i)
+}
+
+// ColumnTypeDatabaseTypeName returns the MySQL type name of column i, such as "VARCHAR" or "BIGINT".
+func (r *rows) ColumnTypeDatabaseTypeName(i int) string {
+	return databaseTypeName(r.columnType(i).typ)
+}
+
+// ColumnTypeScanType returns a Go type suitable for scanning column i.
+func (r *rows) ColumnTypeScanType(i int) reflect.Type {
+	return r.columnType(i).scanType()
+}
+
+// ColumnTypeNullable reports whether column i may be NULL.
+// Undeclared columns are only known to be nullable if they contain a NULL.
+func (r *rows) ColumnTypeNullable(i int) (nullable, ok bool) {
+	ct := r.columnType(i)
+	return ct.nullable, ct.nullOK
+}
+
+// ColumnTypeLength returns the length of text and binary columns.
+func (r *rows) ColumnTypeLength(i int) (length int64, ok bool) {
+	ct := r.columnType(i)
+	return ct.length, ct.lenOK
+}
+
+// ColumnTypePrecisionScale returns the precision and scale of declared DECIMAL and floating point columns.
+func (r *rows) ColumnTypePrecisionScale(i int) (precision, scale int64, ok bool) {
+	ct := r.columnType(i)
+	return ct.prec, ct.scale, ct.precOK
+}
+
 func (r *rows) Next(dest []driver.Value) error {
+	if r.err != nil {
+		return r.err
+	}
+	if r.fail.err != nil && r.read >= r.fail.after {
+		r.err = r.fail.err
+		return r.err
+	}
 	r.cursor++
 	if r.cursor > len(r.data) {
 		r.closed = true
@@ -47,38 +173,211 @@ func (r *rows) Next(dest []driver.Value) error {
 	for i, col := range r.data[r.cursor-1] {
 		dest[i] = col
 	}
+	r.read++
 
 	return nil
 }
 
+// CSVType is a type hint for a CSV column, used with TypeHint.
+type CSVType string
+
+// CSV column types
+const (
+	// CSVBytes is []byte. This is the default, unless the cell is a time matching the ParseTime layout.
+	CSVBytes CSVType = "bytes"
+	// CSVString is string.
+	CSVString CSVType = "string"
+	// CSVInt64 is int64.
+	CSVInt64 CSVType = "int64"
+	// CSVFloat64 is float64.
+	CSVFloat64 CSVType = "float64"
+	// CSVBool is bool, parsed with strconv.ParseBool.
+	CSVBool CSVType = "bool"
+	// CSVTime is time.Time, parsed with the ParseTime layout, RFC 3339, or MySQL's DATETIME and DATE formats.
+	CSVTime CSVType = "time"
+)
+
 // cribbed from DATA-DOG/go-sqlmock
 // TODO rewrite
-func csvToValues(cols []string, s string) [][]driver.Value {
-	var data [][]driver.Value
+func csvToValues(cols []string, s string, hints map[string]CSVType) ([][]driver.Value, error) {
+	return csvRecordsToValues(cols, csvRecords(s), hints)
+}
+
+// csvRecords splits CSV data into rows of cells.
+func csvRecords(s string) [][]string {
 	if s == "" {
 		return nil
 	}
-
-	res := strings.NewReader(strings.TrimSpace(s))
-	csvReader := csv.NewReader(res)
-
+	var records [][]string
+	csvReader := csv.NewReader(strings.NewReader(strings.TrimSpace(s)))
 	for {
 		res, err := csvReader.Read()
 		if err != nil || res == nil {
 			break
 		}
+		records = append(records, res)
+	}
+	return records
+}
+
+// csvRecordsToValues converts CSV cells to values.
+// Cells matching a ParseNull token are nil, and cells of columns with type hints are converted to that type.
+func csvRecordsToValues(cols []string, records [][]string, hints map[string]CSVType) ([][]driver.Value, error) {
+	var data [][]driver.Value
+	timeLayout := parseTimeLayout()
+	nulls := parseNullTokens()
 
-		row := []driver.Value{}
-		for _, v := range res {
+	for _, res := range records {
+		row := make([]driver.Value, 0, len(res))
+	cells:
+		for i, v := range res {
+			trimmed := strings.TrimSpace(v)
+			for _, null := range nulls {
+				if trimmed == null {
+					row = append(row, nil)
+					continue cells
+				}
+			}
+			if i < len(cols) {
+				if typ, ok := hints[strings.ToLower(cols[i])]; ok {
+					val, err := typ.convert(trimmed, timeLayout)
+					if err != nil {
+						return nil, fmt.Errorf("mogi: CSV column %s: %v", cols[i], err)
+					}
+					row = append(row, val)
+					continue
+				}
+			}
 			if timeLayout != "" {
 				if t, err := time.Parse(timeLayout, v); err == nil {
 					row = append(row, t)
 					continue
 				}
 			}
-			row = append(row, []byte(strings.TrimSpace(v)))
+			row = append(row, []byte(trimmed))
 		}
 		data = append(data, row)
 	}
-	return data
+	return data, nil
+}
+
+func (typ CSVType) convert(v string, timeLayout string) (driver.Value, error) {
+	switch typ {
+	case CSVBytes:
+		return []byte(v), nil
+	case CSVString:
+		return v, nil
+	case CSVInt64:
+		return strconv.ParseInt(v, 10, 64)
+	case CSVFloat64:
+		return strconv.ParseFloat(v, 64)
+	case CSVBool:
+		return strconv.ParseBool(v)
+	case CSVTime:
+		layouts := []string{time.RFC3339Nano, "2006-01-02 15:04:05", "2006-01-02"}
+		if timeLayout != "" {
+			layouts = append([]string{timeLayout}, layouts...)
+		}
+		for _, layout := range layouts {
+			if t, err := time.Parse(layout, v); err == nil {
+				return t, nil
+			}
+		}
+		return nil, fmt.Errorf("can't parse time: %q", v)
+	}
+	return nil, fmt.Errorf("unknown type: %s", typ)
+}
+
+// newTypeHints returns a copy of hints with the given hint added, by lowercase column name.
+func newTypeHints(hints map[string]CSVType, col string, typ CSVType) map[string]CSVType {
+	cp := make(map[string]CSVType, len(hints)+1)
+	for k, v := range hints {
+		cp[k] = v
+	}
+	cp[strings.ToLower(col)] = typ
+	return cp
+}
+
+// csvHeader returns the column names in the first row of CSV data.
+func csvHeader(s string) []string {
+	r := csv.NewReader(strings.NewReader(strings.TrimSpace(s)))
+	row, err := r.Read()
+	if err != nil {
+		return nil
+	}
+	header := make([]string, len(row))
+	for i, col := range row {
+		header[i] = strings.TrimSpace(col)
+	}
+	return header
+}
+
+// projectCSV converts CSV data with a header row to values,
+// picking out the given columns in order. "*" is every column in the header.
+func projectCSV(cols []string, s string, hints map[string]CSVType) ([][]driver.Value, error) {
+	records := csvRecords(s)
+	if len(records) == 0 {
+		return nil, nil
+	}
+	header := csvHeader(s)
+	idx := make([]int, 0, len(cols))
+	for _, col := range cols {
+		if col == "*" {
+			for i := range header {
+				idx = append(idx, i)
+			}
+			continue
+		}
+		i := headerIndex(header, col)
+		if i == -1 {
+			return nil, fmt.Errorf("mogi: column %s is not in the CSV header (%s)", col, strings.Join(header, ", "))
+		}
+		idx = append(idx, i)
+	}
+
+	// skip header
+	data, err := csvRecordsToValues(header, records[1:], hints)
+	if err != nil {
+		return nil, err
+	}
+	projected := make([][]driver.Value, len(data))
+	for n, row := range data {
+		projected[n] = make([]driver.Value, len(idx))
+		for j, i := range idx {
+			if i < len(row) {
+				projected[n][j] = row[i]
+			}
+		}
+	}
+	return projected, nil
+}
+
+// headerIndex returns the index of col in header, trying the unqualified name for qualified columns.
+func headerIndex(header []string, col string) int {
+	for i, name := range header {
+		if strings.EqualFold(name, col) {
+			return i
+		}
+	}
+	if dot := strings.LastIndexByte(col, '.'); dot != -1 {
+		return headerIndex(header, col[dot+1:])
+	}
+	return -1
+}
+
+// expandStar replaces "*" in cols with the columns of header.
+// cols is returned as is if there is no header.
+func expandStar(cols []string, header []string) []string {
+	if header == nil {
+		return cols
+	}
+	expanded := make([]string, 0, len(cols))
+	for _, col := range cols {
+		if col == "*" {
+			expanded = append(expanded, header...)
+			continue
+		}
+		expanded = append(expanded, col)
+	}
+	return expanded
 }
@@ -12,7 +12,7 @@ type selectCond struct {
 	cols []string
 }
 
-func (sc selectCond) matches(in input) bool {
+func (sc selectCond) matches(in *input) bool {
 	_, ok := in.statement.(*sqlparser.Select)
 	if !ok {
 		return false
@@ -45,15 +45,11 @@ type fromCond struct {
 	tables []string
 }
 
-func (fc fromCond) matches(in input) bool {
-	var inTables []string
-	switch x := in.statement.(type) {
-	case *sqlparser.Select:
-		for _, tex := range x.From {
-			extractTableNames(&inTables, tex)
-		}
+func (fc fromCond) matches(in *input) bool {
+	if _, ok := in.statement.(*sqlparser.Select); !ok {
+		return false
 	}
-	return reflect.DeepEqual(lowercase(fc.tables), lowercase(inTables))
+	return reflect.DeepEqual(lowercase(fc.tables), lowercase(in.tables()))
 }
 
 func (fc fromCond) priority() int {
@@ -66,3 +62,75 @@ func (fc fromCond) priority() int {
 func (fc fromCond) String() string {
 	return fmt.Sprintf("FROM %s", strings.Join(fc.tables, ", "))
 }
+
+type orderByCond struct {
+	terms []string
+	canon []string
+	err   error // if terms couldn't be parsed
+}
+
+func newOrderByCond(dialect Dialect, terms []string) orderByCond {
+	oc := orderByCond{terms: terms}
+	if len(terms) == 0 {
+		return oc
+	}
+	query := "SELECT 1 FROM t ORDER BY " + strings.Join(terms, ", ")
+	stmt, err := sqlparser.Parse(dialect.translate(query))
+	if err != nil {
+		oc.err = fmt.Errorf("mogi: bad ORDER BY %q: %w", strings.Join(terms, ", "), err)
+		return oc
+	}
+	sel, ok := stmt.(*sqlparser.Select)
+	if !ok || len(sel.OrderBy) == 0 || sel.Limit != nil || sel.Lock != "" {
+		oc.err = fmt.Errorf("mogi: bad ORDER BY %q: not a list of ORDER BY terms", strings.Join(terms, ", "))
+		return oc
+	}
+	oc.canon = canonicalOrder(sel.OrderBy)
+	return oc
+}
+
+func (oc orderByCond) matches(in *input) bool {
+	if oc.err != nil {
+		return false
+	}
+	sel, ok := in.statement.(*sqlparser.Select)
+	if !ok {
+		return false
+	}
+	return reflect.DeepEqual(oc.canon, canonicalOrder(sel.OrderBy))
+}
+
+func (oc orderByCond) invalid() error {
+	return oc.err
+}
+
+func (oc orderByCond) priority() int {
+	return 1
+}
+
+func (oc orderByCond) String() string {
+	if len(oc.terms) == 0 {
+		return "ORDER BY (none)"
+	}
+	return fmt.Sprintf("ORDER BY %s", strings.Join(oc.terms, ", "))
+}
+
+// canonicalOrder returns each ORDER BY term as "expr asc" or "expr desc",
+// followed by "nulls first" or "nulls last" if given, with column qualifiers removed.
+func canonicalOrder(order sqlparser.OrderBy) []string {
+	var canon []string
+	for _, o := range order {
+		var expr string
+		if col, ok := o.Expr.(*sqlparser.ColName); ok {
+			expr = string(col.Name)
+		} else {
+			expr = sqlparser.String(o.Expr)
+		}
+		term := strings.ToLower(expr) + " " + o.Direction
+		if o.Nulls != "" {
+			term += " " + o.Nulls
+		}
+		canon = append(canon, term)
+	}
+	return canon
+}
@@ -2,8 +2,11 @@ package mogi_test
 
 import (
 	"database/sql"
+	"database/sql/driver"
+	"errors"
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/guregu/mogi"
 )
@@ -165,7 +168,7 @@ func TestSelectStar(t *testing.T) {
 
 func runUnstubbedSelect(t *testing.T, db *sql.DB) {
 	_, err := db.Query("SELECT id, name, brewery, pct FROM beer WHERE pct > ?", 5)
-	if err != mogi.ErrUnstubbed {
+	if !errors.Is(err, mogi.ErrUnstubbed) {
 		t.Error("with unmatched query, err should be ErrUnstubbed but is", err)
 	}
 }
@@ -198,3 +201,343 @@ func checkBeer(t *testing.T, b beer, id int) {
 		t.Error("beers don't match", b, "≠", cmp, id)
 	}
 }
+
+func TestStubCSVWithHeader(t *testing.T) {
+	defer mogi.Reset()
+	db := openDB()
+
+	mogi.Select().From("beer").StubCSVWithHeader(`pct,name,id,brewery
+		5.5,Yona Yona Ale,1,Yo-Ho Brewing
+		5.6,Punk IPA,2,BrewDog`)
+	runBeerSelectQuery(t, db)
+
+	// subset of columns
+	var name string
+	err := db.QueryRow("SELECT beer.name FROM beer").Scan(&name)
+	checkNil(t, err)
+	if name != "Yona Yona Ale" {
+		t.Error("bad name:", name)
+	}
+
+	// star
+	rows, err := db.Query("SELECT * FROM beer")
+	checkNil(t, err)
+	cols, err := rows.Columns()
+	checkNil(t, err)
+	if !reflect.DeepEqual(cols, []string{"pct", "name", "id", "brewery"}) {
+		t.Error("bad columns:", cols)
+	}
+	rows.Close()
+
+	// missing column
+	_, err = db.Query("SELECT id, abv FROM beer")
+	if err == nil {
+		t.Error("expected an error for a missing column")
+	}
+}
+
+func TestStubCSVTypes(t *testing.T) {
+	defer mogi.Reset()
+	defer mogi.ParseNull()
+	db := openDB()
+
+	mogi.ParseNull("NULL", `\N`)
+	mogi.Select("id", "name", "pct", "organic", "brewed_at").
+		TypeHint("id", mogi.CSVInt64).
+		TypeHint("pct", mogi.CSVFloat64).
+		TypeHint("organic", mogi.CSVBool).
+		TypeHint("brewed_at", mogi.CSVTime).
+		StubCSV(`1,Yona Yona Ale,5.5,true,2016-01-02 15:04:05
+		2,NULL,\N,0,NULL`)
+
+	rows, err := db.Query("SELECT id, name, pct, organic, brewed_at FROM beer")
+	checkNil(t, err)
+	defer rows.Close()
+
+	var (
+		id       interface{}
+		name     sql.NullString
+		pct      sql.NullFloat64
+		organic  interface{}
+		brewedAt *time.Time
+	)
+	rows.Next()
+	checkNil(t, rows.Scan(&id, &name, &pct, &organic, &brewedAt))
+	if id != int64(1) || name.String != "Yona Yona Ale" || pct.Float64 != 5.5 || organic != true {
+		t.Error("bad values:", id, name, pct, organic)
+	}
+	if brewedAt == nil || !brewedAt.Equal(time.Date(2016, 1, 2, 15, 4, 5, 0, time.UTC)) {
+		t.Error("bad time:", brewedAt)
+	}
+	rows.Next()
+	checkNil(t, rows.Scan(&id, &name, &pct, &organic, &brewedAt))
+	if name.Valid || pct.Valid || organic != false || brewedAt != nil {
+		t.Error("bad values:", name, pct, organic, brewedAt)
+	}
+
+	// bad data
+	mogi.Reset()
+	mogi.Select("id").TypeHint("id", mogi.CSVInt64).StubCSV(`one`)
+	_, err = db.Query("SELECT id FROM beer")
+	if err == nil {
+		t.Error("expected an error for bad CSV data")
+	}
+}
+
+func TestStubResultSets(t *testing.T) {
+	defer mogi.Reset()
+	db := openDB()
+
+	mogi.Select().From("beer").TypeHint("count", mogi.CSVInt64).StubResultSets(
+		mogi.ResultSet{CSV: beerCSV},
+		mogi.ResultSet{Columns: []string{"count"}, CSV: `2`},
+		mogi.ResultSet{Columns: []string{"brewery"}, Rows: [][]driver.Value{{"BrewDog"}, {"Yo-Ho Brewing"}}},
+	)
+
+	rows, err := db.Query("SELECT id, name, brewery, pct FROM beer")
+	checkNil(t, err)
+	defer rows.Close()
+
+	var beers []beer
+	for rows.Next() {
+		var b beer
+		checkNil(t, rows.Scan(&b.id, &b.name, &b.brewery, &b.pct))
+		beers = append(beers, b)
+	}
+	if len(beers) != 2 {
+		t.Error("first result set should have 2 rows but has", len(beers))
+	}
+
+	if !rows.NextResultSet() {
+		t.Fatal("expected a second result set:", rows.Err())
+	}
+	cols, err := rows.Columns()
+	checkNil(t, err)
+	if !reflect.DeepEqual(cols, []string{"count"}) {
+		t.Error("bad columns:", cols)
+	}
+	var count int64
+	if !rows.Next() {
+		t.Fatal("expected a row")
+	}
+	checkNil(t, rows.Scan(&count))
+	if count != 2 {
+		t.Error("count should be 2 but is", count)
+	}
+
+	if !rows.NextResultSet() {
+		t.Fatal("expected a third result set:", rows.Err())
+	}
+	var breweries []string
+	for rows.Next() {
+		var brewery string
+		checkNil(t, rows.Scan(&brewery))
+		breweries = append(breweries, brewery)
+	}
+	if !reflect.DeepEqual(breweries, []string{"BrewDog", "Yo-Ho Brewing"}) {
+		t.Error("bad breweries:", breweries)
+	}
+
+	if rows.NextResultSet() {
+		t.Error("there should be no more result sets")
+	}
+	checkNil(t, rows.Err())
+}
+
+func TestFailAfterRows(t *testing.T) {
+	defer mogi.Reset()
+	db := openDB()
+
+	errDropped := errors.New("connection dropped")
+	mogi.Select().From("beer").FailAfterRows(1, errDropped).StubCSV(beerCSV)
+
+	rows, err := db.Query("SELECT id, name, brewery, pct FROM beer")
+	checkNil(t, err)
+	n := 0
+	for rows.Next() {
+		n++
+	}
+	if n != 1 {
+		t.Error("should have read 1 row but read", n)
+	}
+	if !errors.Is(rows.Err(), errDropped) {
+		t.Error("rows.Err() should be", errDropped, "but is", rows.Err())
+	}
+
+	// closing early
+	errClose := errors.New("close failed")
+	mogi.Reset()
+	mogi.Select().From("beer").FailOnClose(errClose).StubCSV(beerCSV)
+	rows, err = db.Query("SELECT id, name, brewery, pct FROM beer")
+	checkNil(t, err)
+	rows.Next()
+	if err := rows.Close(); !errors.Is(err, errClose) {
+		t.Error("rows.Close() should be", errClose, "but is", err)
+	}
+
+	// RETURNING
+	mogi.Reset()
+	mogi.Insert().Into("beer").FailAfterRows(0, errDropped).StubCSV(`1`)
+	err = db.QueryRow("INSERT INTO beer (name) VALUES (?) RETURNING id", "Punk IPA").Scan(new(int))
+	if !errors.Is(err, errDropped) {
+		t.Error("err should be", errDropped, "but is", err)
+	}
+}
+
+func TestApplyLimit(t *testing.T) {
+	defer mogi.Reset()
+	db := openDB()
+
+	mogi.Select("pct").From("beer").ApplyLimit(mogi.ErrUnresolved).StubCSV(`5.5
+		5.6
+		4.6
+		18.2`)
+
+	checkTablePcts(t, db, "SELECT pct FROM beer LIMIT 2", nil, 5.5, 5.6)
+	checkTablePcts(t, db, "SELECT pct FROM beer LIMIT ?, ?", []interface{}{2, 2}, 4.6, 18.2)
+	checkTablePcts(t, db, "SELECT pct FROM beer LIMIT 10 OFFSET 3", nil, 18.2)
+	checkTablePcts(t, db, "SELECT pct FROM beer OFFSET 2", nil, 4.6, 18.2)
+	checkTablePcts(t, db, "SELECT pct FROM beer OFFSET 1 LIMIT 2", nil, 5.6, 4.6)
+	checkTablePcts(t, db, "SELECT pct FROM beer", nil, 5.5, 5.6, 4.6, 18.2)
+
+	_, err := db.Query("SELECT pct FROM beer LIMIT 2 OFFSET 4")
+	if !errors.Is(err, mogi.ErrUnresolved) {
+		t.Error("err should be ErrUnresolved but is", err)
+	}
+
+	// no error past the end
+	mogi.Reset()
+	mogi.Select("pct").From("beer").ApplyLimit(nil).StubCSV(`5.5`)
+	checkTablePcts(t, db, "SELECT pct FROM beer LIMIT 1 OFFSET 1", nil)
+}
+
+func TestOrderBy(t *testing.T) {
+	defer mogi.Reset()
+	db := openDB()
+
+	mogi.Select().From("beer").OrderBy("pct DESC", "id").StubCSV(beerCSV)
+	mogi.Select().From("beer").OrderBy().StubError(sql.ErrNoRows)
+
+	queries := []struct {
+		query string
+		err   error
+	}{
+		{"SELECT id, name, brewery, pct FROM beer ORDER BY pct DESC, id", nil},
+		{"SELECT id, name, brewery, pct FROM beer ORDER BY beer.PCT desc, id ASC", nil},
+		{"SELECT id, name, brewery, pct FROM beer", sql.ErrNoRows},
+		{"SELECT id, name, brewery, pct FROM beer ORDER BY pct, id", mogi.ErrUnstubbed},
+		{"SELECT id, name, brewery, pct FROM beer ORDER BY id, pct DESC", mogi.ErrUnstubbed},
+		{"SELECT id, name, brewery, pct FROM beer ORDER BY pct DESC", mogi.ErrUnstubbed},
+	}
+	for _, q := range queries {
+		_, err := db.Query(q.query)
+		if !errors.Is(err, q.err) {
+			t.Error(q.query, "error should be", q.err, "but is", err)
+		}
+	}
+
+	mogi.Reset()
+	mogi.Select().From("beer").OrderBy("pct DESC NULLS LAST").StubCSV(beerCSV)
+	_, err := db.Query("SELECT id, name, brewery, pct FROM beer ORDER BY pct DESC NULLS LAST")
+	checkNil(t, err)
+	_, err = db.Query("SELECT id, name, brewery, pct FROM beer ORDER BY pct DESC")
+	if !errors.Is(err, mogi.ErrUnstubbed) {
+		t.Error("error should be ErrUnstubbed but is", err)
+	}
+
+	// unparsable terms
+	mogi.Reset()
+	mogi.Select().From("beer").OrderBy("pct DESC DESC").StubCSV(beerCSV)
+	_, err = db.Query("SELECT id, name, brewery, pct FROM beer ORDER BY pct DESC")
+	if err == nil || errors.Is(err, mogi.ErrUnstubbed) {
+		t.Error("expected an error for bad ORDER BY terms, got:", err)
+	}
+	for _, terms := range []string{"a LIMIT 1 UNION SELECT 1 FROM t", "a LIMIT 1"} {
+		mogi.Reset()
+		mogi.Select().From("beer").OrderBy(terms).StubCSV(beerCSV)
+		_, err = db.Query("SELECT id, name, brewery, pct FROM beer ORDER BY a")
+		if err == nil || errors.Is(err, mogi.ErrUnstubbed) {
+			t.Error(terms, "expected an error for bad ORDER BY terms, got:", err)
+		}
+	}
+}
+
+func TestSortRows(t *testing.T) {
+	defer mogi.Reset()
+	db := openDB()
+
+	mogi.ParseNull("NULL")
+	defer mogi.ParseNull()
+	mogi.Select("pct").From("beer").SortRows().ApplyLimit(nil).StubCSV(`5.6
+		18.2
+		NULL
+		4.6
+		5.5`)
+
+	checkTablePcts(t, db, "SELECT pct FROM beer ORDER BY pct DESC LIMIT 4", nil, 18.2, 5.6, 5.5, 4.6)
+	checkTablePcts(t, db, "SELECT pct FROM beer ORDER BY pct DESC LIMIT 2", nil, 18.2, 5.6)
+	checkTablePcts(t, db, "SELECT pct FROM beer ORDER BY pct LIMIT 2 OFFSET 1", nil, 4.6, 5.5)
+	// unsorted
+	checkTablePcts(t, db, "SELECT pct FROM beer LIMIT 2", nil, 5.6, 18.2)
+	// explicit NULL order
+	checkTablePcts(t, db, "SELECT pct FROM beer ORDER BY pct NULLS LAST LIMIT 4", nil, 4.6, 5.5, 5.6, 18.2)
+
+	// NULLs first in MySQL, last in PostgreSQL
+	var pct sql.NullFloat64
+	checkNil(t, db.QueryRow("SELECT pct FROM beer ORDER BY pct DESC NULLS FIRST").Scan(&pct))
+	if pct.Valid {
+		t.Error("NULL should sort first with NULLS FIRST but got", pct)
+	}
+	checkNil(t, db.QueryRow("SELECT pct FROM beer ORDER BY pct").Scan(&pct))
+	if pct.Valid {
+		t.Error("NULL should sort first but got", pct)
+	}
+	mogi.SetDialect(mogi.PostgreSQL)
+	defer mogi.SetDialect(mogi.MySQL)
+	checkNil(t, db.QueryRow("SELECT pct FROM beer ORDER BY pct DESC").Scan(&pct))
+	if pct.Valid {
+		t.Error("NULL should sort first descending in PostgreSQL but got", pct)
+	}
+
+	// unknown column
+	_, err := db.Query("SELECT pct FROM beer ORDER BY abv")
+	if err == nil {
+		t.Error("expected an error for an unknown column")
+	}
+
+	// rows missing values
+	mogi.Reset()
+	mogi.Select().From("beer").SortRows().Stub([][]driver.Value{{int64(1)}, {int64(2)}})
+	_, err = db.Query("SELECT id, name FROM beer ORDER BY name")
+	if err == nil {
+		t.Error("expected an error for short rows")
+	}
+
+	// only the first result set is sorted and limited
+	mogi.Reset()
+	mogi.SetDialect(mogi.MySQL)
+	mogi.Select("pct").From("beer").SortRows().ApplyLimit(nil).StubResultSets(
+		mogi.ResultSet{CSV: "5.6\n4.6\n5.5"},
+		mogi.ResultSet{CSV: "18.2\n1.0"},
+	)
+	rows, err := db.Query("SELECT pct FROM beer ORDER BY pct LIMIT 2")
+	checkNil(t, err)
+	defer rows.Close()
+	var sets [][]float64
+	for {
+		var set []float64
+		for rows.Next() {
+			var f float64
+			checkNil(t, rows.Scan(&f))
+			set = append(set, f)
+		}
+		sets = append(sets, set)
+		if !rows.NextResultSet() {
+			break
+		}
+	}
+	checkNil(t, rows.Err())
+	if want := [][]float64{{4.6, 5.5}, {18.2, 1.0}}; !reflect.DeepEqual(sets, want) {
+		t.Error("bad result sets:", sets, "≠", want)
+	}
+}
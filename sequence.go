@@ -0,0 +1,69 @@
+package mogi
+
+import (
+	"fmt"
+	"sync"
+)
+
+// Sequence is a group of stubs that must be matched in order.
+// Use InSequence to add stubs to a sequence.
+// A stub in a sequence can only match after all the stubs before it have matched
+// as many times as they expect (once, unless limited with Times, AtLeast, etc.).
+// Once a later stub has matched, earlier stubs can't match again.
+// Queries matching a stub out of order fall through to other stubs,
+// and return an error wrapping ErrOutOfOrder if none match.
+type Sequence struct {
+	mu    sync.Mutex
+	steps []seqStep
+	cur   int // the latest step that matched, or -1
+}
+
+type seqStep struct {
+	chain *condchain
+	count *expectation // limits of the stub, its calls are guarded by its registry
+	calls int
+}
+
+// min returns how many times this step must match before the next step can.
+func (step seqStep) min() int {
+	if step.count.limited() {
+		return step.count.min
+	}
+	return 1
+}
+
+// NewSequence creates a new, empty sequence.
+func NewSequence() *Sequence {
+	return &Sequence{cur: -1}
+}
+
+// add appends a step to the sequence and returns its position
+func (seq *Sequence) add(chain *condchain, count *expectation) int {
+	seq.mu.Lock()
+	defer seq.mu.Unlock()
+	seq.steps = append(seq.steps, seqStep{chain: chain, count: count})
+	return len(seq.steps) - 1
+}
+
+// advance marks the step at pos as matched,
+// or returns an error if the step at pos isn't allowed to match now.
+func (seq *Sequence) advance(pos int, in *input) error {
+	seq.mu.Lock()
+	defer seq.mu.Unlock()
+	if pos < seq.cur && seq.steps[pos].calls >= seq.steps[pos].min() {
+		return fmt.Errorf("%w: step %d of %d (%s) can't match after step %d (%s): %s",
+			ErrOutOfOrder, pos+1, len(seq.steps), *seq.steps[pos].chain, seq.cur+1, *seq.steps[seq.cur].chain, in.query)
+	}
+	for i := 0; i < pos; i++ {
+		step := seq.steps[i]
+		if step.calls < step.min() {
+			return fmt.Errorf("%w: expected step %d of %d (%s), but got step %d (%s): %s",
+				ErrOutOfOrder, i+1, len(seq.steps), *step.chain, pos+1, *seq.steps[pos].chain, in.query)
+		}
+	}
+	seq.steps[pos].calls++
+	if pos > seq.cur {
+		seq.cur = pos
+	}
+	return nil
+}
@@ -0,0 +1,131 @@
+package mogi_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/guregu/mogi"
+)
+
+func TestSequence(t *testing.T) {
+	defer mogi.Reset()
+	db := openDB()
+
+	seq := mogi.NewSequence()
+	mogi.Select().From("beer").Where("id", 3).InSequence(seq).StubCSV(`3,Mikkel’s Dream,Mikkeller,4.6`)
+	mogi.Update().Table("beer").Where("id", 3).InSequence(seq).StubRowsAffected(1)
+	mogi.Insert().Into("audit").InSequence(seq).StubResult(1, 1)
+
+	// in order
+	rows, err := db.Query("SELECT id, name, brewery, pct FROM beer WHERE id = ? FOR UPDATE", 3)
+	checkNil(t, err)
+	rows.Close()
+	_, err = db.Exec("UPDATE beer SET pct = ? WHERE id = ?", 4.7, 3)
+	checkNil(t, err)
+	_, err = db.Exec("INSERT INTO audit (beer_id, action) VALUES (?, ?)", 3, "update")
+	checkNil(t, err)
+
+	tb := &fakeTB{TB: t}
+	mogi.Verify(tb)
+	if len(tb.errors) != 0 {
+		t.Error("unexpected errors:", tb.errors)
+	}
+}
+
+func TestSequenceOutOfOrder(t *testing.T) {
+	defer mogi.Reset()
+	db := openDB()
+
+	seq := mogi.NewSequence()
+	mogi.Select().From("beer").Where("id", 3).InSequence(seq).StubCSV(`3,Mikkel’s Dream,Mikkeller,4.6`)
+	mogi.Update().Table("beer").Where("id", 3).InSequence(seq).StubRowsAffected(1)
+	mogi.Insert().Into("audit").InSequence(seq).StubResult(1, 1)
+
+	rows, err := db.Query("SELECT id, name, brewery, pct FROM beer WHERE id = ? FOR UPDATE", 3)
+	checkNil(t, err)
+	rows.Close()
+	// skipped the update
+	_, err = db.Exec("INSERT INTO audit (beer_id, action) VALUES (?, ?)", 3, "update")
+	if !errors.Is(err, mogi.ErrOutOfOrder) {
+		t.Fatal("err should be ErrOutOfOrder but is", err)
+	}
+	for _, part := range []string{
+		"expected step 2 of 3 (UPDATE (any) TABLE beer WHERE id ≈ [3])",
+		"got step 3 (INSERT (any) TABLE audit)",
+		"INSERT INTO audit",
+	} {
+		if !strings.Contains(err.Error(), part) {
+			t.Errorf("error %q should contain %q", err, part)
+		}
+	}
+
+	tb := &fakeTB{TB: t}
+	mogi.Verify(tb)
+	if len(tb.errors) != 3 {
+		t.Error("expected 2 unused stubs and 1 out of order query, got", tb.errors)
+	}
+
+	// back on track
+	_, err = db.Exec("UPDATE beer SET pct = ? WHERE id = ?", 4.7, 3)
+	checkNil(t, err)
+	_, err = db.Exec("INSERT INTO audit (beer_id, action) VALUES (?, ?)", 3, "update")
+	checkNil(t, err)
+}
+
+func TestSequenceTimes(t *testing.T) {
+	defer mogi.Reset()
+	db := openDB()
+
+	seq := mogi.NewSequence()
+	mogi.Insert().Into("beer").Times(2).InSequence(seq).StubResult(1, 1)
+	mogi.Update().Table("beer").InSequence(seq).StubRowsAffected(1)
+
+	_, err := db.Exec("INSERT INTO beer (name) VALUES (?)", "Punk IPA")
+	checkNil(t, err)
+	// the insert is expected twice
+	_, err = db.Exec("UPDATE beer SET pct = ? WHERE id = ?", 4.7, 3)
+	if !errors.Is(err, mogi.ErrOutOfOrder) {
+		t.Error("err should be ErrOutOfOrder but is", err)
+	}
+	_, err = db.Exec("INSERT INTO beer (name) VALUES (?)", "Tokyo*")
+	checkNil(t, err)
+	_, err = db.Exec("UPDATE beer SET pct = ? WHERE id = ?", 4.7, 3)
+	checkNil(t, err)
+}
+
+func TestSequenceStrict(t *testing.T) {
+	defer mogi.Reset()
+	db := openDB()
+
+	seq := mogi.NewSequence()
+	mogi.Insert().Into("beer").InSequence(seq).StubResult(1, 1)
+	mogi.Update().Table("beer").InSequence(seq).StubRowsAffected(1)
+	// not in the sequence
+	mogi.Insert().Priority(-10).StubResult(2, 1)
+
+	_, err := db.Exec("INSERT INTO beer (name) VALUES (?)", "Punk IPA")
+	checkNil(t, err)
+	_, err = db.Exec("UPDATE beer SET pct = ? WHERE id = ?", 4.7, 3)
+	checkNil(t, err)
+	// the first step can't match again, so it falls through
+	res, err := db.Exec("INSERT INTO beer (name) VALUES (?)", "Tokyo*")
+	checkNil(t, err)
+	if id, _ := res.LastInsertId(); id != 2 {
+		t.Error("should have fallen through to the other stub, but got ID", id)
+	}
+
+	// with nothing to fall through to
+	mogi.Reset()
+	seq = mogi.NewSequence()
+	mogi.Insert().Into("beer").InSequence(seq).StubResult(1, 1)
+	mogi.Update().Table("beer").InSequence(seq).StubRowsAffected(1)
+	_, err = db.Exec("INSERT INTO beer (name) VALUES (?)", "Punk IPA")
+	checkNil(t, err)
+	_, err = db.Exec("UPDATE beer SET pct = ? WHERE id = ?", 4.7, 3)
+	checkNil(t, err)
+	_, err = db.Exec("INSERT INTO beer (name) VALUES (?)", "Tokyo*")
+	if !errors.Is(err, mogi.ErrOutOfOrder) {
+		t.Error("err should be ErrOutOfOrder but is", err)
+	}
+}
@@ -1,10 +1,12 @@
 package mogi
 
 import (
+	"context"
 	"database/sql/driver"
 )
 
 type stmt struct {
+	conn  *conn
 	query string
 }
 
@@ -28,11 +30,21 @@ func (s *stmt) NumInput() int {
 // Exec executes a query that doesn't return rows, such
 // as an INSERT or UPDATE.
 func (s *stmt) Exec(args []driver.Value) (driver.Result, error) {
-	return drv.conn.Exec(s.query, args)
+	return s.conn.Exec(s.query, args)
+}
+
+// ExecContext is the context-aware version of Exec.
+func (s *stmt) ExecContext(ctx context.Context, args []driver.NamedValue) (driver.Result, error) {
+	return s.conn.ExecContext(ctx, s.query, args)
 }
 
 // Query executes a query that may return rows, such as a
 // SELECT.
 func (s *stmt) Query(args []driver.Value) (driver.Rows, error) {
-	return drv.conn.Query(s.query, args)
+	return s.conn.Query(s.query, args)
+}
+
+// QueryContext is the context-aware version of Query.
+func (s *stmt) QueryContext(ctx context.Context, args []driver.NamedValue) (driver.Rows, error) {
+	return s.conn.QueryContext(ctx, s.query, args)
 }
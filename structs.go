@@ -0,0 +1,139 @@
+package mogi
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// structRows converts a slice of structs (or maps) to rows with the given columns.
+// Struct fields are named by their db tag, or by their field name if they don't have one.
+func structRows(cols []string, slice reflect.Value) ([][]driver.Value, error) {
+	data := make([][]driver.Value, 0, slice.Len())
+	for i := 0; i < slice.Len(); i++ {
+		item := slice.Index(i)
+		for item.Kind() == reflect.Ptr || item.Kind() == reflect.Interface {
+			item = item.Elem()
+		}
+
+		var fields map[string]reflect.Value
+		switch item.Kind() {
+		case reflect.Struct:
+			fields = make(map[string]reflect.Value)
+			structFields(fields, nil, item, item.Type())
+		case reflect.Map:
+			fields = make(map[string]reflect.Value, item.Len())
+			iter := item.MapRange()
+			for iter.Next() {
+				fields[strings.ToLower(fmt.Sprint(iter.Key().Interface()))] = iter.Value()
+			}
+		default:
+			return nil, fmt.Errorf("mogi: StubStructs: item %d is %s, not a struct or map", i, item.Kind())
+		}
+
+		row := make([]driver.Value, len(cols))
+		for j, col := range cols {
+			field, ok := lookupField(fields, col)
+			if !ok {
+				return nil, fmt.Errorf("mogi: StubStructs: item %d has no field for column %s", i, col)
+			}
+			if !field.IsValid() {
+				// field of a nil embedded pointer
+				continue
+			}
+			v, err := driver.DefaultParameterConverter.ConvertValue(field.Interface())
+			if err != nil {
+				return nil, fmt.Errorf("mogi: StubStructs: item %d column %s: %v", i, col, err)
+			}
+			row[j] = v
+		}
+		data = append(data, row)
+	}
+	return data, nil
+}
+
+// structFields adds the fields of rv (of type t) to fields by lowercase name, including embedded structs,
+// and returns names with the names of the added fields appended in order.
+// Fields of nil embedded pointers are added as invalid values.
+func structFields(fields map[string]reflect.Value, names []string, rv reflect.Value, t reflect.Type) []string {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		var fv reflect.Value
+		if rv.IsValid() {
+			fv = rv.Field(i)
+		}
+		if field.Anonymous {
+			switch {
+			case field.Type.Kind() == reflect.Struct:
+				names = structFields(fields, names, fv, field.Type)
+				continue
+			case field.Type.Kind() == reflect.Ptr && field.Type.Elem().Kind() == reflect.Struct:
+				if fv.IsValid() && !fv.IsNil() {
+					fv = fv.Elem()
+				} else {
+					fv = reflect.Value{}
+				}
+				names = structFields(fields, names, fv, field.Type.Elem())
+				continue
+			}
+		}
+		if field.PkgPath != "" {
+			// unexported
+			continue
+		}
+		name := field.Name
+		if tag := field.Tag.Get("db"); tag != "" {
+			name = strings.Split(tag, ",")[0]
+		}
+		if name == "-" {
+			continue
+		}
+		name = strings.ToLower(name)
+		if _, ok := fields[name]; !ok {
+			fields[name] = fv
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// lookupField finds the field for the given column, trying unqualified names for qualified columns.
+func lookupField(fields map[string]reflect.Value, col string) (reflect.Value, bool) {
+	col = strings.ToLower(col)
+	if field, ok := fields[col]; ok {
+		return field, true
+	}
+	if i := strings.LastIndexByte(col, '.'); i != -1 {
+		field, ok := fields[col[i+1:]]
+		return field, ok
+	}
+	return reflect.Value{}, false
+}
+
+// structColumns returns the column names of the struct type of slice's items, in field order,
+// for expanding "*". It returns nil for maps, which have no column order.
+func structColumns(slice reflect.Value) []string {
+	t := slice.Type().Elem()
+	if t.Kind() == reflect.Interface && slice.Len() > 0 {
+		if item := slice.Index(0); !item.IsNil() {
+			t = item.Elem().Type()
+		}
+	}
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return nil
+	}
+	return structFields(make(map[string]reflect.Value), []string{}, reflect.Value{}, t)
+}
+
+// checkStructs returns an error if v isn't a slice or array.
+func checkStructs(v interface{}) (reflect.Value, error) {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+		return rv, fmt.Errorf("mogi: StubStructs: want a slice of structs or maps, got %T", v)
+	}
+	return rv, nil
+}
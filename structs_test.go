@@ -0,0 +1,133 @@
+package mogi_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/guregu/mogi"
+)
+
+type beerRow struct {
+	ID      int64   `db:"id"`
+	Name    string  `db:"name"`
+	Brewery string  `db:"brewery"`
+	Pct     float64 `db:"pct"`
+}
+
+type brewedBeer struct {
+	beerRow
+	BrewedAt *time.Time `db:"brewed_at"`
+	Secret   string     `db:"-"`
+}
+
+func TestStubStructs(t *testing.T) {
+	defer mogi.Reset()
+	db := openDB()
+
+	mogi.Select().From("beer").StubStructs([]beerRow{
+		{1, "Yona Yona Ale", "Yo-Ho Brewing", 5.5},
+		{2, "Punk IPA", "BrewDog", 5.6},
+	})
+	runBeerSelectQuery(t, db)
+
+	// different order, aliases, and qualifiers
+	var b beer
+	err := db.QueryRow("SELECT beer.pct, brewery, name AS Name, id FROM beer").Scan(&b.pct, &b.brewery, &b.name, &b.id)
+	checkNil(t, err)
+	checkBeer(t, b, 1)
+
+	// missing column
+	_, err = db.Query("SELECT id, abv FROM beer")
+	if err == nil {
+		t.Error("expected an error for a missing column")
+	}
+
+	// embedded structs, pointers, and maps
+	mogi.Reset()
+	mogi.Select().From("beer").StubStructs([]*brewedBeer{
+		{beerRow: beerRow{ID: 1, Name: "Yona Yona Ale"}, Secret: "🍺"},
+	})
+	var id int64
+	var brewedAt *time.Time
+	err = db.QueryRow("SELECT id, brewed_at FROM beer").Scan(&id, &brewedAt)
+	checkNil(t, err)
+	if id != 1 || brewedAt != nil {
+		t.Error("bad values:", id, brewedAt)
+	}
+	_, err = db.Query("SELECT secret FROM beer")
+	if err == nil {
+		t.Error("expected an error for an ignored field")
+	}
+
+	mogi.Reset()
+	mogi.Select().StubStructs([]map[string]interface{}{
+		{"id": 2, "name": "Punk IPA", "brewery": "BrewDog", "pct": 5.6},
+	})
+	err = db.QueryRow("SELECT name, id, pct, brewery FROM beer").Scan(&b.name, &b.id, &b.pct, &b.brewery)
+	checkNil(t, err)
+	checkBeer(t, b, 2)
+}
+
+type labeledBeer struct {
+	*beerRow
+	Label string `db:"label"`
+}
+
+func TestStubStructsStar(t *testing.T) {
+	defer mogi.Reset()
+	db := openDB()
+
+	mogi.Select().From("beer").StubStructs([]beerRow{
+		{1, "Yona Yona Ale", "Yo-Ho Brewing", 5.5},
+	})
+	rows, err := db.Query("SELECT * FROM beer")
+	checkNil(t, err)
+	cols, err := rows.Columns()
+	checkNil(t, err)
+	if want := []string{"id", "name", "brewery", "pct"}; !reflect.DeepEqual(cols, want) {
+		t.Error("bad columns:", cols, "≠", want)
+	}
+	var b beer
+	if !rows.Next() {
+		t.Fatal("no rows")
+	}
+	checkNil(t, rows.Scan(&b.id, &b.name, &b.brewery, &b.pct))
+	checkBeer(t, b, 1)
+	checkNil(t, rows.Close())
+
+	// embedded pointers
+	mogi.Reset()
+	mogi.Select().From("beer").StubStructs([]labeledBeer{
+		{&beerRow{ID: 1, Name: "Yona Yona Ale"}, "Yona"},
+		{nil, "?"},
+	})
+	rows, err = db.Query("SELECT * FROM beer")
+	checkNil(t, err)
+	cols, err = rows.Columns()
+	checkNil(t, err)
+	if want := []string{"id", "name", "brewery", "pct", "label"}; !reflect.DeepEqual(cols, want) {
+		t.Error("bad columns:", cols, "≠", want)
+	}
+	var ids []*int64
+	for rows.Next() {
+		var id *int64
+		var name, brewery, label *string
+		var pct *float64
+		checkNil(t, rows.Scan(&id, &name, &brewery, &pct, &label))
+		ids = append(ids, id)
+	}
+	checkNil(t, rows.Err())
+	if len(ids) != 2 || ids[0] == nil || *ids[0] != 1 || ids[1] != nil {
+		t.Error("bad ids:", ids)
+	}
+
+	// not a slice
+	mogi.Reset()
+	mogi.Select().From("beer").StubStructs(beerRow{ID: 1})
+	_, err = db.Query("SELECT id FROM beer")
+	if err == nil || !strings.Contains(err.Error(), "want a slice") {
+		t.Error("expected an error for a non-slice, got:", err)
+	}
+}
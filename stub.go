@@ -1,16 +1,34 @@
 package mogi
 
 import (
+	"database/sql"
 	"database/sql/driver"
+	"time"
+
+	"github.com/guregu/mogi/internal/sqlparser"
 )
 
 // Stub is a SQL query stub (for SELECT)
 type Stub struct {
+	reg   *Registry
 	chain condchain
 	data  [][]driver.Value
 	err   error
+	count expectation
+	delay latency
+	fail  rowErrors
+
+	seq    *Sequence
+	seqPos int
 
-	resolve func(input)
+	header  []string              // for StubCSVWithHeader and StubStructs
+	hints   map[string]CSVType    // by lowercase column name
+	types   map[string]columnType // by lowercase column name
+	typeErr error                 // from ColumnType
+	resolve func(in *input) ([][]driver.Value, error)
+	sets    []ResultSet // for StubResultSets
+	limit   *limitOption
+	sort    bool // for SortRows
 }
 
 type subquery struct {
@@ -21,10 +39,16 @@ type subquery struct {
 // You can filter out which columns to use this stub for.
 // If you don't pass any columns, it will stub all SELECT queries.
 func Select(cols ...string) *Stub {
+	return drv.registry("").Select(cols...)
+}
+
+func newStub(reg *Registry, cols []string) *Stub {
 	return &Stub{
+		reg: reg,
 		chain: condchain{selectCond{
 			cols: cols,
 		}},
+		count: newExpectation(),
 	}
 }
 
@@ -39,20 +63,135 @@ func (s *Stub) From(tables ...string) *Stub {
 
 // Where further filters this stub by values of input in the WHERE clause.
 // You can pass multiple values for IN clause matching.
+// Values are found anywhere in the WHERE clause, so OR is treated like AND,
+// and if a column appears more than once only its last value is used.
+// Use WhereExpr to match the structure of the WHERE clause.
 func (s *Stub) Where(col string, v ...interface{}) *Stub {
 	s.chain = append(s.chain, newWhereCond(col, v))
 	return s
 }
 
 // WhereOp further filters this stub by values of input and the operator used in the WHERE clause.
+// Operators include "=", "<", "like", "not in", "between" (with two values), and "is null" (with no values).
+// Like Where, OR is treated like AND, and only the last use of a column with the same operator is used.
 func (s *Stub) WhereOp(col string, operator string, v ...interface{}) *Stub {
 	s.chain = append(s.chain, newWhereOpCond(col, v, operator))
 	return s
 }
 
+// WhereBetween further filters this stub by a BETWEEN in the WHERE clause, such as: WHERE col BETWEEN lo AND hi.
+func (s *Stub) WhereBetween(col string, lo, hi interface{}) *Stub {
+	s.chain = append(s.chain, newWhereOpCond(col, []interface{}{lo, hi}, "between"))
+	return s
+}
+
+// WhereNull further filters this stub by an IS NULL in the WHERE clause, such as: WHERE deleted_at IS NULL.
+func (s *Stub) WhereNull(col string) *Stub {
+	s.chain = append(s.chain, newWhereOpCond(col, nil, "is null"))
+	return s
+}
+
+// WhereNotNull further filters this stub by an IS NOT NULL in the WHERE clause.
+func (s *Stub) WhereNotNull(col string) *Stub {
+	s.chain = append(s.chain, newWhereOpCond(col, nil, "is not null"))
+	return s
+}
+
+// WhereLike further filters this stub by a LIKE in the WHERE clause, comparing the pattern as a string.
+func (s *Stub) WhereLike(col string, pattern string) *Stub {
+	s.chain = append(s.chain, newWhereOpCond(col, []interface{}{pattern}, "like"))
+	return s
+}
+
+// WhereNotIn further filters this stub by a NOT IN in the WHERE clause.
+func (s *Stub) WhereNotIn(col string, v ...interface{}) *Stub {
+	s.chain = append(s.chain, newWhereOpCond(col, v, "not in"))
+	return s
+}
+
+// OrderBy further filters this stub by the ORDER BY clause, such as OrderBy("created_at DESC", "id").
+// The terms must match in order, and ASC is the default direction.
+// With no terms, it matches only queries without an ORDER BY.
+// NULLS FIRST and NULLS LAST must match too, if given.
+// If the terms can't be parsed, queries this stub would otherwise match return an error.
+func (s *Stub) OrderBy(terms ...string) *Stub {
+	s.chain = append(s.chain, newOrderByCond(s.reg.Dialect(), terms))
+	return s
+}
+
+// WhereExpr further filters this stub by the logical structure of the WHERE clause, such as "a = 1 OR b = ?".
+// Placeholders in expr are replaced by args, and placeholders in the query by the query's args.
+// Parentheses and the order of the operands of AND and OR don't matter.
+// If expr is malformed, queries that would otherwise match this stub return an error describing it.
+func (s *Stub) WhereExpr(expr string, args ...interface{}) *Stub {
+	s.chain = append(s.chain, newWhereExprCond(s.reg.Dialect(), expr, args))
+	return s
+}
+
+// WhereRow further filters this stub, matching queries whose WHERE clause would be satisfied by the given row.
+// The row's keys are column names. Queries using a column missing from the row don't match.
+func (s *Stub) WhereRow(row map[string]interface{}) *Stub {
+	s.chain = append(s.chain, newWhereRowCond(row))
+	return s
+}
+
 // Args further filters this stub, matching based on the args passed to the query
 func (s *Stub) Args(args ...driver.Value) *Stub {
-	s.chain = append(s.chain, argsCond{args})
+	s.chain = append(s.chain, newArgsCond(args))
+	return s
+}
+
+// ArgNamed further filters this stub, matching based on the named arg (sql.Named) passed to the query
+func (s *Stub) ArgNamed(name string, v interface{}) *Stub {
+	s.chain = append(s.chain, newArgNamedCond(name, v))
+	return s
+}
+
+// InTx further filters this stub, matching queries run inside of a transaction.
+func (s *Stub) InTx() *Stub {
+	s.chain = append(s.chain, txCond{})
+	return s
+}
+
+// Isolation further filters this stub, matching queries run inside of a transaction
+// with the given isolation level (see sql.TxOptions).
+func (s *Stub) Isolation(level sql.IsolationLevel) *Stub {
+	s.chain = append(s.chain, isolationCond{level})
+	return s
+}
+
+// ReadOnly further filters this stub, matching queries run inside of a transaction
+// whose read-only option (see sql.TxOptions) is readOnly.
+func (s *Stub) ReadOnly(readOnly bool) *Stub {
+	s.chain = append(s.chain, readOnlyCond{readOnly})
+	return s
+}
+
+// Match further filters this stub with a custom matcher, adding the given priority.
+// fn is called without holding any of mogi's locks, so it may call other functions of this package,
+// but it may be called concurrently for queries on different connections.
+func (s *Stub) Match(fn func(q Query) bool, priority int) *Stub {
+	s.chain = append(s.chain, matchCond{fn: fn, p: priority})
+	return s
+}
+
+// TypeHint sets the type of a column for CSV data, so its cells are converted from text to that type.
+// col is the name of the column in the query.
+func (s *Stub) TypeHint(col string, typ CSVType) *Stub {
+	s.hints = newTypeHints(s.hints, col, typ)
+	return s
+}
+
+// ColumnType declares the type of a result column, as reported by sql.Rows.ColumnTypes.
+// decl is a MySQL column type such as "BIGINT UNSIGNED", "VARCHAR(255) NOT NULL", or "DECIMAL(10,2)".
+// Columns without a declared type have their type inferred from the stubbed values.
+// If decl is not a known type, matching queries return an error.
+func (s *Stub) ColumnType(col string, decl string) *Stub {
+	types, err := newColumnTypes(s.types, col, decl)
+	if err != nil && s.typeErr == nil {
+		s.typeErr = err
+	}
+	s.types = types
 	return s
 }
 
@@ -75,42 +214,267 @@ func (s *Stub) Dump() *Stub {
 	return s
 }
 
+// Times limits this stub to matching n times.
+// Once used up, queries fall through to the next stub.
+// ExpectationsWereMet will report this stub unless it matched exactly n times.
+func (s *Stub) Times(n int) *Stub {
+	s.count.times(n)
+	return s
+}
+
+// Once is a shortcut for Times(1).
+func (s *Stub) Once() *Stub {
+	return s.Times(1)
+}
+
+// AtMost limits this stub to matching at most n times.
+// Once used up, queries fall through to the next stub.
+func (s *Stub) AtMost(n int) *Stub {
+	s.count.atMost(n)
+	return s
+}
+
+// AtLeast has ExpectationsWereMet report this stub unless it matched at least n times.
+// It doesn't limit matching.
+func (s *Stub) AtLeast(n int) *Stub {
+	s.count.atLeast(n)
+	return s
+}
+
+// Never is a shortcut for Times(0).
+// This stub will never match, but ExpectationsWereMet will report it if it would have.
+func (s *Stub) Never() *Stub {
+	return s.Times(0)
+}
+
+// Delay makes queries matching this stub wait for d before returning.
+// If the query's context is cancelled first, the query returns the context's error.
+func (s *Stub) Delay(d time.Duration) *Stub {
+	s.delay = latency{delay: d}
+	return s
+}
+
+// DelayJitter is like Delay, but adds a random duration between 0 and jitter to each wait.
+func (s *Stub) DelayJitter(d, jitter time.Duration) *Stub {
+	s.delay = latency{delay: d, jitter: jitter}
+	return s
+}
+
+// SortRows makes this stub sort its rows by the query's ORDER BY, so they can be given in any order.
+// Terms refer to the query's columns by name. NULLs sort first in ascending order,
+// unless the dialect is PostgreSQL, which sorts them last. NULLS FIRST and NULLS LAST override this.
+// Rows are sorted before ApplyLimit picks them out.
+// With StubResultSets, only the first result set is sorted.
+func (s *Stub) SortRows() *Stub {
+	s.sort = true
+	return s
+}
+
+// ApplyLimit makes this stub return only the rows picked out by the query's LIMIT and OFFSET,
+// so one set of data can be used for every page of a paginated query.
+// If the OFFSET is past the end of the data, the query fails with pastEnd (such as ErrUnresolved).
+// If pastEnd is nil, it returns no rows instead.
+// With StubResultSets, only the first result set is limited.
+func (s *Stub) ApplyLimit(pastEnd error) *Stub {
+	s.limit = &limitOption{pastEnd: pastEnd}
+	return s
+}
+
+// FailAfterRows makes reading the rows fail with err after n rows have been read,
+// like a connection dropping halfway through a result.
+// sql.Rows.Next will return false and sql.Rows.Err will return err.
+func (s *Stub) FailAfterRows(n int, err error) *Stub {
+	s.fail.after, s.fail.err = n, err
+	return s
+}
+
+// FailOnClose makes closing the rows return err.
+// sql.Rows.Close only returns it if the rows are closed before they are all read.
+func (s *Stub) FailOnClose(err error) *Stub {
+	s.fail.closeErr = err
+	return s
+}
+
+// InSequence adds this stub to the end of the given sequence.
+// It will only match after the stubs added before it have matched, and not after the stubs added after it.
+func (s *Stub) InSequence(seq *Sequence) *Stub {
+	s.seq = seq
+	s.seqPos = seq.add(&s.chain, &s.count)
+	return s
+}
+
 // StubCSV takes CSV data and registers this stub with the driver
 func (s *Stub) StubCSV(data string) {
-	s.resolve = func(in input) {
-		s.data = csvToValues(in.cols(), data)
+	s.resolve = func(in *input) ([][]driver.Value, error) {
+		return csvToValues(in.cols(), data, s.hints)
+	}
+	s.reg.addStub(s)
+}
+
+// StubCSVWithHeader takes CSV data whose first row names the columns, and registers this stub with the driver.
+// The columns are picked out and reordered to match the query, and SELECT * returns all of them.
+// Queries selecting a column missing from the header return an error.
+func (s *Stub) StubCSVWithHeader(data string) {
+	s.header = csvHeader(data)
+	s.resolve = func(in *input) ([][]driver.Value, error) {
+		return projectCSV(in.cols(), data, s.hints)
 	}
-	addStub(s)
+	s.reg.addStub(s)
 }
 
 // Stub takes row data and registers this stub with the driver
 func (s *Stub) Stub(rows [][]driver.Value) {
 	s.data = rows
-	addStub(s)
+	s.reg.addStub(s)
+}
+
+// StubStructs takes a slice of structs (or maps) and registers this stub with the driver.
+// Each column of the query is taken from the field with the same name in its db tag (or field name),
+// so the order of the columns doesn't matter. Columns without fields return an error.
+// SELECT * returns every field of the struct in order, including fields of embedded structs
+// (fields of a nil embedded pointer are NULL). Maps have no column order, so they don't support SELECT *.
+// If slice isn't a slice or array, queries return an error.
+func (s *Stub) StubStructs(slice interface{}) {
+	rv, err := checkStructs(slice)
+	if err == nil {
+		s.header = structColumns(rv)
+	}
+	s.resolve = func(in *input) ([][]driver.Value, error) {
+		if err != nil {
+			return nil, err
+		}
+		return structRows(expandStar(in.cols(), s.header), rv)
+	}
+	s.reg.addStub(s)
+}
+
+// StubFunc registers this stub to return the rows (or error) given by fn,
+// which is called with each matching query.
+// Use this to return data that depends on the query, like echoing its args.
+func (s *Stub) StubFunc(fn func(q Query) ([][]driver.Value, error)) {
+	s.resolve = func(in *input) ([][]driver.Value, error) {
+		return fn(Query{in: in})
+	}
+	s.reg.addStub(s)
+}
+
+// StubResultSets registers this stub to return several result sets, in order,
+// like a stored procedure or a multi-statement query.
+// Move to the next one with sql.Rows.NextResultSet.
+// SortRows and ApplyLimit apply to the first result set.
+func (s *Stub) StubResultSets(sets ...ResultSet) {
+	s.sets = sets
+	s.reg.addStub(s)
 }
 
 // StubError registers this stub to return the given error
 func (s *Stub) StubError(err error) {
 	s.err = err
-	addStub(s)
+	s.reg.addStub(s)
 }
 
 func (s *Stub) Subquery() subquery {
 	return subquery{chain: s.chain}
 }
 
-func (s *Stub) matches(in input) bool {
+func (s *Stub) matches(in *input) bool {
 	return s.chain.matches(in)
 }
 
-func (s *Stub) rows(in input) (*rows, error) {
+func (s *Stub) rows(in *input) (*rows, error) {
+	data := s.data
 	switch {
 	case s.err != nil:
 		return nil, s.err
-	case s.data == nil && s.resolve != nil:
-		s.resolve(in)
+	case s.typeErr != nil:
+		return nil, s.typeErr
+	case s.sets != nil:
+		r, err := resultSetRows(in.cols(), s.sets, s.hints)
+		if err != nil {
+			return nil, err
+		}
+		// SortRows and ApplyLimit only apply to the first result set
+		if r.data, err = s.shape(in, r.cols, r.data); err != nil {
+			return nil, err
+		}
+		r.types = s.types
+		r.fail = s.fail
+		return r, nil
+	case data == nil && s.resolve != nil:
+		var err error
+		if data, err = s.resolve(in); err != nil {
+			return nil, err
+		}
+	}
+	cols := expandStar(in.cols(), s.header)
+	data, err := s.shape(in, cols, data)
+	if err != nil {
+		return nil, err
+	}
+	r := newRows(cols, data)
+	r.types = s.types
+	r.fail = s.fail
+	return r, nil
+}
+
+// shape sorts and limits data according to SortRows and ApplyLimit.
+func (s *Stub) shape(in *input, cols []string, data [][]driver.Value) ([][]driver.Value, error) {
+	var err error
+	if s.sort {
+		if data, err = sortRows(in, cols, data); err != nil {
+			return nil, err
+		}
+	}
+	if s.limit != nil {
+		if data, err = s.limit.apply(in, data); err != nil {
+			return nil, err
+		}
+	}
+	return data, nil
+}
+
+// sortRows returns a sorted copy of data, ordered by the query's ORDER BY.
+func sortRows(in *input, cols []string, data [][]driver.Value) ([][]driver.Value, error) {
+	sel, ok := in.statement.(*sqlparser.Select)
+	if !ok || len(sel.OrderBy) == 0 {
+		return data, nil
+	}
+	idx := make([]int, len(data))
+	for i := range idx {
+		idx[i] = i
+	}
+	if err := sortByOrder(in, sel.OrderBy, cols, idx, func(i int) []driver.Value { return data[i] }); err != nil {
+		return nil, err
+	}
+	sorted := make([][]driver.Value, len(data))
+	for n, i := range idx {
+		sorted[n] = data[i]
+	}
+	return sorted, nil
+}
+
+type limitOption struct {
+	pastEnd error
+}
+
+// apply returns the rows of data picked out by the query's LIMIT and OFFSET.
+func (lo *limitOption) apply(in *input, data [][]driver.Value) ([][]driver.Value, error) {
+	sel, ok := in.statement.(*sqlparser.Select)
+	if !ok || sel.Limit == nil {
+		return data, nil
+	}
+	offset, err := limitOffset(in, sel.Limit)
+	if err != nil {
+		return nil, err
+	}
+	if offset > 0 && offset >= int64(len(data)) && lo.pastEnd != nil {
+		return nil, lo.pastEnd
+	}
+	from, to, err := limits(in, sel.Limit, len(data))
+	if err != nil {
+		return nil, err
 	}
-	return newRows(in.cols(), s.data), nil
+	return data[from:to], nil
 }
 
 func (s *Stub) priority() int {
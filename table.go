@@ -0,0 +1,702 @@
+package mogi
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/guregu/mogi/internal/sqlparser"
+)
+
+// TableStub is an in-memory table.
+// Queries using a table that don't match any stub are run against its rows:
+// INSERTs add rows, UPDATEs and DELETEs change the rows matching their WHERE clause,
+// and SELECTs return the matching rows. This lets you test code that reads what it wrote.
+// A TableStub is safe for concurrent use.
+type TableStub struct {
+	name string
+	cols []string
+
+	mu      sync.Mutex
+	hints   map[string]CSVType
+	autoInc int // index of the auto increment column, or -1
+	nextID  int64
+	data    [][]driver.Value
+}
+
+// Table declares an in-memory table with the given columns,
+// replacing any table with the same name. Tables are removed by Reset.
+func Table(name string, cols ...string) *TableStub {
+	return drv.registry("").Table(name, cols...)
+}
+
+func newTableStub(name string, cols []string) *TableStub {
+	t := &TableStub{
+		name:    name,
+		cols:    cols,
+		autoInc: -1,
+		nextID:  1,
+	}
+	for i, col := range cols {
+		if strings.EqualFold(col, "id") {
+			t.autoInc = i
+			break
+		}
+	}
+	return t
+}
+
+// Seed adds rows to the table from CSV data, in the same format as StubCSV.
+// It panics if the data doesn't match the table's type hints.
+func (t *TableStub) Seed(data string) *TableStub {
+	t.mu.Lock()
+	hints := t.hints
+	t.mu.Unlock()
+	rows, err := csvToValues(t.cols, data, hints)
+	if err != nil {
+		panic(err)
+	}
+	return t.SeedRows(rows)
+}
+
+// TypeHint sets the type of a column for seeding with CSV data.
+func (t *TableStub) TypeHint(col string, typ CSVType) *TableStub {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.hints = newTypeHints(t.hints, col, typ)
+	return t
+}
+
+// SeedRows adds rows to the table.
+func (t *TableStub) SeedRows(rows [][]driver.Value) *TableStub {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for _, row := range rows {
+		r := make([]driver.Value, len(t.cols))
+		for i := range r {
+			if i < len(row) {
+				r[i] = unify(row[i])
+			}
+		}
+		t.data = append(t.data, r)
+		t.seen(r)
+	}
+	return t
+}
+
+// AutoIncrement sets the column that INSERTs fill with the next ID when they leave it out or set it to NULL.
+// The ID is returned by LastInsertId. By default, this is the "id" column if there is one.
+// An empty string disables auto increment.
+func (t *TableStub) AutoIncrement(col string) *TableStub {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.autoInc = t.colIndex(col)
+	t.nextID = 1
+	for _, row := range t.data {
+		t.seen(row)
+	}
+	return t
+}
+
+// Rows returns a copy of the table's rows.
+func (t *TableStub) Rows() [][]driver.Value {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	rows := make([][]driver.Value, len(t.data))
+	for i, row := range t.data {
+		rows[i] = append([]driver.Value(nil), row...)
+	}
+	return rows
+}
+
+// String returns the table's name.
+func (t *TableStub) String() string {
+	return t.name
+}
+
+// rows runs a query against this table, returning the selected rows.
+// INSERTs, UPDATEs, and DELETEs return the rows of their RETURNING clause.
+func (t *TableStub) rows(in *input) (*rows, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if err := t.checkColumns(in.statement); err != nil {
+		return nil, err
+	}
+
+	switch x := in.statement.(type) {
+	case *sqlparser.Select:
+		return t.selectRows(in, x)
+	case *sqlparser.Insert:
+		inserted, _, err := t.insert(in, x)
+		if err != nil {
+			return nil, err
+		}
+		return t.project(in, sqlparser.SelectExprs(x.Returning), inserted)
+	case *sqlparser.Update:
+		updated, err := t.update(in, x)
+		if err != nil {
+			return nil, err
+		}
+		return t.project(in, sqlparser.SelectExprs(x.Returning), updated)
+	case *sqlparser.Delete:
+		deleted, err := t.delete(in, x)
+		if err != nil {
+			return nil, err
+		}
+		return t.project(in, sqlparser.SelectExprs(x.Returning), deleted)
+	}
+	return nil, t.unsupported(in.kind())
+}
+
+// results runs a statement against this table.
+func (t *TableStub) results(in *input) (driver.Result, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if err := t.checkColumns(in.statement); err != nil {
+		return nil, err
+	}
+
+	switch x := in.statement.(type) {
+	case *sqlparser.Select:
+		rows, err := t.selectRows(in, x)
+		if err != nil {
+			return nil, err
+		}
+		return execResult{lastInsertID: -1, rowsAffected: int64(len(rows.data))}, nil
+	case *sqlparser.Insert:
+		inserted, id, err := t.insert(in, x)
+		if err != nil {
+			return nil, err
+		}
+		return execResult{lastInsertID: id, rowsAffected: int64(len(inserted))}, nil
+	case *sqlparser.Update:
+		updated, err := t.update(in, x)
+		if err != nil {
+			return nil, err
+		}
+		return execResult{lastInsertID: -1, rowsAffected: int64(len(updated))}, nil
+	case *sqlparser.Delete:
+		deleted, err := t.delete(in, x)
+		if err != nil {
+			return nil, err
+		}
+		return execResult{lastInsertID: -1, rowsAffected: int64(len(deleted))}, nil
+	}
+	return nil, t.unsupported(in.kind())
+}
+
+func (t *TableStub) selectRows(in *input, stmt *sqlparser.Select) (*rows, error) {
+	if len(stmt.From) != 1 {
+		return nil, t.unsupported("joins")
+	}
+	ate, ok := stmt.From[0].(*sqlparser.AliasedTableExpr)
+	if !ok {
+		return nil, t.unsupported("joins")
+	}
+	if _, ok := ate.Expr.(*sqlparser.TableName); !ok {
+		return nil, t.unsupported("subqueries")
+	}
+	if len(stmt.GroupBy) > 0 || stmt.Having != nil {
+		return nil, t.unsupported("GROUP BY")
+	}
+
+	idx, err := t.find(in, stmt.Where, t.resolveAliases(stmt.OrderBy, stmt.SelectExprs))
+	if err != nil {
+		return nil, err
+	}
+	data := make([][]driver.Value, len(idx))
+	for n, i := range idx {
+		data[n] = t.data[i]
+	}
+
+	var result *rows
+	if isAggregate(stmt.SelectExprs) {
+		result, err = t.aggregate(in, stmt.SelectExprs, data)
+	} else {
+		result, err = t.project(in, stmt.SelectExprs, data)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if stmt.Distinct != "" {
+		result.data = distinct(result.data)
+	}
+	lo, hi, err := limits(in, stmt.Limit, len(result.data))
+	if err != nil {
+		return nil, err
+	}
+	result.data = result.data[lo:hi]
+	return result, nil
+}
+
+// insert adds the rows of an INSERT, returning them and the ID of the first one.
+func (t *TableStub) insert(in *input, stmt *sqlparser.Insert) (inserted [][]driver.Value, lastInsertID int64, err error) {
+	values, ok := stmt.Rows.(sqlparser.Values)
+	if !ok {
+		return nil, -1, t.unsupported("INSERT ... SELECT")
+	}
+	if len(stmt.OnDup) > 0 {
+		return nil, -1, t.unsupported("ON DUPLICATE KEY UPDATE")
+	}
+
+	cols := in.cols()
+	if len(cols) == 0 {
+		cols = t.cols
+	}
+	idx := make([]int, len(cols))
+	for i, col := range cols {
+		if idx[i] = t.colIndex(col); idx[i] == -1 {
+			return nil, -1, t.unknownColumn(col)
+		}
+	}
+
+	// build every row first, so that a bad INSERT changes nothing
+	lastInsertID = -1
+	nextID := t.nextID
+	e := evaluator{in: in}
+	for _, tuple := range values {
+		vals, ok := tuple.(sqlparser.ValTuple)
+		if !ok {
+			return nil, -1, t.unsupported("subqueries")
+		}
+		if len(vals) != len(cols) {
+			return nil, -1, fmt.Errorf("mogi: table %s: got %d values for %d columns", t.name, len(vals), len(cols))
+		}
+		row := make([]driver.Value, len(t.cols))
+		for j, expr := range vals {
+			if row[idx[j]], err = e.value(expr); err != nil {
+				return nil, -1, err
+			}
+		}
+		if t.autoInc != -1 {
+			// like LAST_INSERT_ID(), only report generated IDs
+			generated := row[t.autoInc] == nil
+			if generated {
+				row[t.autoInc] = nextID
+			}
+			id, ok := toInt(row[t.autoInc])
+			if ok && id >= nextID {
+				nextID = id + 1
+			}
+			if lastInsertID == -1 && generated {
+				lastInsertID = id
+			}
+		}
+		inserted = append(inserted, row)
+	}
+
+	if t.autoInc != -1 && lastInsertID == -1 {
+		lastInsertID = 0
+	}
+	t.data = append(t.data, inserted...)
+	t.nextID = nextID
+	return inserted, lastInsertID, nil
+}
+
+// update changes the rows matching an UPDATE, returning the updated rows.
+func (t *TableStub) update(in *input, stmt *sqlparser.Update) ([][]driver.Value, error) {
+	idx, err := t.find(in, stmt.Where, stmt.OrderBy)
+	if err != nil {
+		return nil, err
+	}
+	lo, hi, err := limits(in, stmt.Limit, len(idx))
+	if err != nil {
+		return nil, err
+	}
+	idx = idx[lo:hi]
+
+	// evaluate every row first, so that a bad UPDATE changes nothing
+	updated := make([][]driver.Value, len(idx))
+	for n, i := range idx {
+		e := evaluator{in: in, cols: t.cols, row: t.data[i]}
+		row := append([]driver.Value(nil), t.data[i]...)
+		for _, expr := range stmt.Exprs {
+			col := t.colIndex(string(expr.Name.Name))
+			if col == -1 {
+				return nil, t.unknownColumn(string(expr.Name.Name))
+			}
+			if row[col], err = e.value(expr.Expr); err != nil {
+				return nil, err
+			}
+		}
+		updated[n] = row
+	}
+
+	for n, i := range idx {
+		t.data[i] = updated[n]
+		t.seen(updated[n])
+	}
+	return updated, nil
+}
+
+// delete removes the rows matching a DELETE, returning the deleted rows.
+func (t *TableStub) delete(in *input, stmt *sqlparser.Delete) ([][]driver.Value, error) {
+	idx, err := t.find(in, stmt.Where, stmt.OrderBy)
+	if err != nil {
+		return nil, err
+	}
+	lo, hi, err := limits(in, stmt.Limit, len(idx))
+	if err != nil {
+		return nil, err
+	}
+	idx = idx[lo:hi]
+
+	doomed := make(map[int]bool, len(idx))
+	deleted := make([][]driver.Value, 0, len(idx))
+	for _, i := range idx {
+		doomed[i] = true
+		deleted = append(deleted, t.data[i])
+	}
+	kept := make([][]driver.Value, 0, len(t.data)-len(idx))
+	for i, row := range t.data {
+		if !doomed[i] {
+			kept = append(kept, row)
+		}
+	}
+	t.data = kept
+	return deleted, nil
+}
+
+// resolveAliases replaces ORDER BY terms naming an alias from the select list with the aliased expression.
+// Columns of the table take precedence.
+func (t *TableStub) resolveAliases(order sqlparser.OrderBy, exprs sqlparser.SelectExprs) sqlparser.OrderBy {
+	var resolved sqlparser.OrderBy
+	for i, o := range order {
+		col, ok := o.Expr.(*sqlparser.ColName)
+		if !ok || col.Qualifier != "" || t.colIndex(string(col.Name)) != -1 {
+			continue
+		}
+		for _, expr := range exprs {
+			nse, ok := expr.(*sqlparser.NonStarExpr)
+			if !ok || !strings.EqualFold(string(nse.As), string(col.Name)) {
+				continue
+			}
+			val, ok := nse.Expr.(sqlparser.ValExpr)
+			if !ok {
+				break
+			}
+			if resolved == nil {
+				resolved = make(sqlparser.OrderBy, len(order))
+				copy(resolved, order)
+			}
+			resolved[i] = &sqlparser.Order{Expr: val, Direction: o.Direction, Nulls: o.Nulls}
+			break
+		}
+	}
+	if resolved == nil {
+		return order
+	}
+	return resolved
+}
+
+// find returns the indexes of the rows satisfying where, sorted by order.
+func (t *TableStub) find(in *input, where *sqlparser.Where, order sqlparser.OrderBy) ([]int, error) {
+	var idx []int
+	for i, row := range t.data {
+		ok, err := evaluator{in: in, cols: t.cols, row: row}.satisfies(where)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			idx = append(idx, i)
+		}
+	}
+	if err := sortByOrder(in, order, t.cols, idx, func(i int) []driver.Value { return t.data[i] }); err != nil {
+		return nil, err
+	}
+	return idx, nil
+}
+
+// project evaluates the select expressions (or RETURNING clause) for each row.
+func (t *TableStub) project(in *input, exprs sqlparser.SelectExprs, data [][]driver.Value) (*rows, error) {
+	if len(exprs) == 0 {
+		// no RETURNING clause
+		return newRows(nil, nil), nil
+	}
+	var cols []string
+	for _, sexpr := range exprs {
+		switch x := sexpr.(type) {
+		case *sqlparser.StarExpr:
+			cols = append(cols, t.cols...)
+		case *sqlparser.NonStarExpr:
+			cols = append(cols, extractColumnName(x))
+		default:
+			return nil, t.unsupported(sqlparser.String(sexpr))
+		}
+	}
+
+	projected := make([][]driver.Value, len(data))
+	for i, row := range data {
+		e := evaluator{in: in, cols: t.cols, row: row}
+		out := make([]driver.Value, 0, len(cols))
+		for _, sexpr := range exprs {
+			switch x := sexpr.(type) {
+			case *sqlparser.StarExpr:
+				out = append(out, row...)
+			case *sqlparser.NonStarExpr:
+				v, err := e.value(x.Expr)
+				if err != nil {
+					return nil, err
+				}
+				out = append(out, v)
+			}
+		}
+		projected[i] = out
+	}
+	return newRows(cols, projected), nil
+}
+
+// aggregate evaluates COUNT(*) and COUNT(col) over the given rows.
+func (t *TableStub) aggregate(in *input, exprs sqlparser.SelectExprs, data [][]driver.Value) (*rows, error) {
+	var cols []string
+	var out []driver.Value
+	for _, sexpr := range exprs {
+		nse, ok := sexpr.(*sqlparser.NonStarExpr)
+		if !ok {
+			return nil, t.unsupported("mixing aggregate and non-aggregate columns")
+		}
+		fn, ok := nse.Expr.(*sqlparser.FuncExpr)
+		if !ok || !strings.EqualFold(fn.Name, "count") || fn.Distinct || len(fn.Exprs) != 1 {
+			return nil, t.unsupported(sqlparser.String(nse.Expr))
+		}
+		cols = append(cols, extractColumnName(nse))
+
+		var count int64
+		switch x := fn.Exprs[0].(type) {
+		case *sqlparser.StarExpr:
+			count = int64(len(data))
+		case *sqlparser.NonStarExpr:
+			for _, row := range data {
+				v, err := evaluator{in: in, cols: t.cols, row: row}.value(x.Expr)
+				if err != nil {
+					return nil, err
+				}
+				if v != nil {
+					count++
+				}
+			}
+		}
+		out = append(out, count)
+	}
+	return newRows(cols, [][]driver.Value{out}), nil
+}
+
+// seen bumps the next auto increment ID past the ID of row.
+func (t *TableStub) seen(row []driver.Value) {
+	if t.autoInc == -1 {
+		return
+	}
+	if id, ok := toInt(row[t.autoInc]); ok && id >= t.nextID {
+		t.nextID = id + 1
+	}
+}
+
+func (t *TableStub) colIndex(name string) int {
+	// ignore qualifiers
+	if i := strings.LastIndexByte(name, '.'); i != -1 {
+		name = name[i+1:]
+	}
+	for i, col := range t.cols {
+		if strings.EqualFold(col, name) {
+			return i
+		}
+	}
+	return -1
+}
+
+// checkColumns returns an error if node uses a column this table doesn't have.
+func (t *TableStub) checkColumns(node sqlparser.SQLNode) error {
+	// ORDER BY can use aliases from the select list
+	aliases := make(map[string]bool)
+	if sel, ok := node.(*sqlparser.Select); ok {
+		for _, expr := range sel.SelectExprs {
+			if nse, ok := expr.(*sqlparser.NonStarExpr); ok && nse.As != "" {
+				aliases[strings.ToLower(string(nse.As))] = true
+			}
+		}
+	}
+	return sqlparser.Walk(func(node sqlparser.SQLNode) (bool, error) {
+		col, ok := node.(*sqlparser.ColName)
+		if !ok {
+			return true, nil
+		}
+		name := string(col.Name)
+		if col.Qualifier == "" && (strings.HasPrefix(name, "@") || aliases[strings.ToLower(name)]) {
+			return false, nil
+		}
+		if t.colIndex(name) == -1 {
+			return false, t.unknownColumn(name)
+		}
+		return false, nil
+	}, node)
+}
+
+func (t *TableStub) unknownColumn(col string) error {
+	return fmt.Errorf("mogi: table %s: unknown column: %s", t.name, col)
+}
+
+func (t *TableStub) unsupported(what string) error {
+	return fmt.Errorf("mogi: table %s: unsupported: %s", t.name, what)
+}
+
+func isAggregate(exprs sqlparser.SelectExprs) bool {
+	for _, sexpr := range exprs {
+		if nse, ok := sexpr.(*sqlparser.NonStarExpr); ok {
+			if fn, ok := nse.Expr.(*sqlparser.FuncExpr); ok && fn.IsAggregate() {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// distinct removes duplicate rows.
+func distinct(data [][]driver.Value) [][]driver.Value {
+	var uniq [][]driver.Value
+	for _, row := range data {
+		dupe := false
+		for _, other := range uniq {
+			if reflect.DeepEqual(row, other) {
+				dupe = true
+				break
+			}
+		}
+		if !dupe {
+			uniq = append(uniq, row)
+		}
+	}
+	return uniq
+}
+
+// limits returns the bounds of the LIMIT clause for n rows.
+func limits(in *input, limit *sqlparser.Limit, n int) (lo, hi int, err error) {
+	if limit == nil {
+		return 0, n, nil
+	}
+	offset, err := limitOffset(in, limit)
+	if err != nil {
+		return 0, 0, err
+	}
+	lo = n
+	if offset < int64(n) {
+		lo = int(offset)
+	}
+	hi = n
+	if limit.Rowcount == nil {
+		// OFFSET without LIMIT
+		return lo, hi, nil
+	}
+
+	v, err := evaluator{in: in}.value(limit.Rowcount)
+	if err != nil {
+		return 0, 0, err
+	}
+	count, ok := toInt(v)
+	if !ok || count < 0 {
+		return 0, 0, fmt.Errorf("mogi: bad limit: %v", v)
+	}
+	if count < int64(n-lo) {
+		hi = lo + int(count)
+	}
+	return lo, hi, nil
+}
+
+// limitOffset returns the OFFSET of limit, or 0 if it has none.
+func limitOffset(in *input, limit *sqlparser.Limit) (int64, error) {
+	if limit == nil || limit.Offset == nil {
+		return 0, nil
+	}
+	v, err := evaluator{in: in}.value(limit.Offset)
+	if err != nil {
+		return 0, err
+	}
+	offset, ok := toInt(v)
+	if !ok || offset < 0 {
+		return 0, fmt.Errorf("mogi: bad offset: %v", v)
+	}
+	return offset, nil
+}
+
+// sortByOrder stably sorts idx by the ORDER BY terms, evaluated against cols and row(i) for each index.
+func sortByOrder(in *input, order sqlparser.OrderBy, cols []string, idx []int, row func(i int) []driver.Value) error {
+	if len(order) == 0 {
+		return nil
+	}
+	keys := make(map[int][]interface{}, len(idx))
+	for _, i := range idx {
+		e := evaluator{in: in, cols: cols, row: row(i)}
+		for _, o := range order {
+			v, err := e.value(o.Expr)
+			if err != nil {
+				return err
+			}
+			keys[i] = append(keys[i], v)
+		}
+	}
+	sort.SliceStable(idx, func(a, b int) bool {
+		for k, o := range order {
+			x, y := keys[idx[a]][k], keys[idx[b]][k]
+			desc := o.Direction == sqlparser.DescScr
+			cmp := compareNulls(x, y, nullsFirst(in, o))
+			if desc && x != nil && y != nil {
+				cmp = -cmp
+			}
+			if cmp != 0 {
+				return cmp < 0
+			}
+		}
+		return false
+	})
+	return nil
+}
+
+// nullsFirst reports whether NULLs come first for the ORDER BY term o.
+// MySQL sorts NULLs first in ascending order, and PostgreSQL sorts them last,
+// unless NULLS FIRST or NULLS LAST is given.
+func nullsFirst(in *input, o *sqlparser.Order) bool {
+	switch o.Nulls {
+	case sqlparser.NullsFirstStr:
+		return true
+	case sqlparser.NullsLastStr:
+		return false
+	}
+	first := in.dialect != PostgreSQL
+	if o.Direction == sqlparser.DescScr {
+		return !first
+	}
+	return first
+}
+
+// compareNulls is like compare, but NULL is the smallest value (or the largest if nullsFirst is false),
+// and values that can't be compared are equal.
+func compareNulls(a, b interface{}, nullsFirst bool) int {
+	switch {
+	case a == nil && b == nil:
+		return 0
+	case a == nil && nullsFirst, b == nil && !nullsFirst:
+		return -1
+	case a == nil, b == nil:
+		return 1
+	}
+	cmp, _ := compare(a, b)
+	return cmp
+}
+
+func toInt(v interface{}) (int64, bool) {
+	switch x := unify(v).(type) {
+	case int64:
+		return x, true
+	case float64:
+		if x == float64(int64(x)) {
+			return int64(x), true
+		}
+	case string:
+		n, err := strconv.ParseInt(strings.TrimSpace(x), 10, 64)
+		return n, err == nil
+	}
+	return 0, false
+}
@@ -0,0 +1,178 @@
+package mogi_test
+
+import (
+	"database/sql"
+	"reflect"
+	"testing"
+
+	"github.com/guregu/mogi"
+)
+
+func TestTable(t *testing.T) {
+	defer mogi.Reset()
+	db := openDB()
+
+	mogi.Table("beer", "id", "name", "brewery", "pct").Seed(beerCSV)
+
+	// seeded rows
+	runBeerSelectQuery(t, db)
+
+	// INSERT, then read it back
+	res, err := db.Exec("INSERT INTO beer (name, brewery, pct) VALUES (?, ?, ?)", "Mikkel’s Dream", "Mikkeller", 4.6)
+	checkNil(t, err)
+	checkRowsAffected(t, res, 1)
+	id, err := res.LastInsertId()
+	checkNil(t, err)
+	if id != 3 {
+		t.Error("LastInsertId should be 3 but is", id)
+	}
+	var b beer
+	err = db.QueryRow("SELECT id, name, brewery, pct FROM beer WHERE name = ?", "Mikkel’s Dream").Scan(&b.id, &b.name, &b.brewery, &b.pct)
+	checkNil(t, err)
+	if b != (beer{3, "Mikkel’s Dream", "Mikkeller", 4.6}) {
+		t.Error("bad inserted beer:", b)
+	}
+
+	// UPDATE, then read it back
+	res, err = db.Exec("UPDATE beer SET pct = pct + 1 WHERE brewery = ? OR id = ?", "BrewDog", 1)
+	checkNil(t, err)
+	checkRowsAffected(t, res, 2)
+	checkTablePcts(t, db, "SELECT pct FROM beer ORDER BY id", nil, 6.5, 6.6, 4.6)
+
+	// DELETE, then read it back
+	res, err = db.Exec("DELETE FROM beer WHERE id IN (?, ?)", 1, 3)
+	checkNil(t, err)
+	checkRowsAffected(t, res, 2)
+	checkTablePcts(t, db, "SELECT pct FROM beer", nil, 6.6)
+
+	// IDs keep counting up
+	res, err = db.Exec("INSERT INTO beer (name, brewery, pct) VALUES (?, ?, ?), (?, ?, ?)",
+		"Tokyo*", "BrewDog", 18.2,
+		"Nodogoshi", "Kirin", 5)
+	checkNil(t, err)
+	checkRowsAffected(t, res, 2)
+	id, err = res.LastInsertId()
+	checkNil(t, err)
+	if id != 4 {
+		t.Error("LastInsertId should be 4 but is", id)
+	}
+
+	// only generated IDs are reported
+	res, err = db.Exec("INSERT INTO beer (id, name) VALUES (?, ?), (NULL, ?)", 10, "Punk IPA", "Elvis Juice")
+	checkNil(t, err)
+	if id, _ = res.LastInsertId(); id != 11 {
+		t.Error("LastInsertId should be 11 but is", id)
+	}
+	res, err = db.Exec("DELETE FROM beer WHERE id IN (10, 11)")
+	checkNil(t, err)
+	checkRowsAffected(t, res, 2)
+	res, err = db.Exec("INSERT INTO beer (id, name) VALUES (?, ?)", 20, "Hardcore IPA")
+	checkNil(t, err)
+	if id, _ = res.LastInsertId(); id != 0 {
+		t.Error("LastInsertId should be 0 but is", id)
+	}
+	_, err = db.Exec("DELETE FROM beer WHERE id = 20")
+	checkNil(t, err)
+
+	var count int
+	err = db.QueryRow("SELECT COUNT(*) FROM beer WHERE NOT (brewery = 'Kirin')").Scan(&count)
+	checkNil(t, err)
+	if count != 2 {
+		t.Error("count should be 2 but is", count)
+	}
+}
+
+func TestTableSelect(t *testing.T) {
+	defer mogi.Reset()
+	db := openDB()
+
+	mogi.Table("beer", "id", "name", "brewery", "pct").Seed(`
+		1,Yona Yona Ale,Yo-Ho Brewing,5.5
+		2,Punk IPA,BrewDog,5.6
+		3,Mikkel’s Dream,Mikkeller,4.6
+		4,Tokyo*,BrewDog,18.2`)
+
+	checkTablePcts(t, db, "SELECT pct FROM beer WHERE pct > 5 ORDER BY pct DESC", nil, 18.2, 5.6, 5.5)
+	checkTablePcts(t, db, "SELECT pct FROM beer WHERE brewery = ? AND pct < ?", []interface{}{"BrewDog", 18}, 5.6)
+	checkTablePcts(t, db, "SELECT pct FROM beer ORDER BY brewery, pct DESC LIMIT 1, 2", nil, 5.6, 4.6)
+	checkTablePcts(t, db, "SELECT beer.pct FROM beer WHERE brewery NOT IN ('BrewDog', 'Mikkeller')", nil, 5.5)
+	checkTablePcts(t, db, "SELECT pct AS abv FROM beer ORDER BY abv DESC LIMIT 2", nil, 18.2, 5.6)
+
+	rows, err := db.Query("SELECT * FROM beer WHERE id = 4")
+	checkNil(t, err)
+	cols, err := rows.Columns()
+	checkNil(t, err)
+	if !reflect.DeepEqual(cols, []string{"id", "name", "brewery", "pct"}) {
+		t.Error("bad columns:", cols)
+	}
+	rows.Close()
+
+	// stubs take precedence
+	mogi.Select().From("beer").StubCSV(`1`)
+	checkTablePcts(t, db, "SELECT pct FROM beer", nil, 1)
+
+	// even for columns the table doesn't have
+	_, err = db.Query("SELECT abv FROM beer")
+	if err != nil {
+		t.Error("stub should have matched, but got", err)
+	}
+
+	// without a stub, unknown columns are an error
+	mogi.Reset()
+	mogi.Table("beer", "id", "name")
+	_, err = db.Query("SELECT abv FROM beer")
+	if err == nil {
+		t.Error("expected an error for an unknown column")
+	}
+}
+
+func TestTableReturning(t *testing.T) {
+	defer mogi.Reset()
+	db := openDB()
+
+	table := mogi.Table("beer", "id", "name", "brewery", "pct").Seed(beerCSV)
+
+	var id int64
+	var name string
+	err := db.QueryRow("INSERT INTO beer (name, brewery, pct) VALUES (?, ?, ?) RETURNING id, name",
+		"Mikkel’s Dream", "Mikkeller", 4.6).Scan(&id, &name)
+	checkNil(t, err)
+	if id != 3 || name != "Mikkel’s Dream" {
+		t.Error("bad RETURNING values:", id, name)
+	}
+
+	err = db.QueryRow("DELETE FROM beer WHERE id = ? RETURNING name", 1).Scan(&name)
+	checkNil(t, err)
+	if name != "Yona Yona Ale" {
+		t.Error("bad RETURNING value:", name)
+	}
+	if n := len(table.Rows()); n != 2 {
+		t.Error("table should have 2 rows but has", n)
+	}
+
+	// history
+	hist := mogi.History()
+	if !hist[len(hist)-1].Matched() || hist[len(hist)-1].Table != table {
+		t.Error("history should record the table")
+	}
+}
+
+func checkTablePcts(t *testing.T, db *sql.DB, query string, args []interface{}, expect ...float64) {
+	t.Helper()
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		t.Error(query, "error:", err)
+		return
+	}
+	defer rows.Close()
+	var pcts []float64
+	for rows.Next() {
+		var pct float64
+		checkNil(t, rows.Scan(&pct))
+		pcts = append(pcts, pct)
+	}
+	checkNil(t, rows.Err())
+	if !reflect.DeepEqual(pcts, expect) {
+		t.Error(query, "got", pcts, "but expected", expect)
+	}
+}
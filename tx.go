@@ -1,12 +1,104 @@
 package mogi
 
+import (
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+)
+
 type tx struct {
+	conn *conn
+	opts driver.TxOptions
 }
 
 func (t *tx) Commit() error {
+	t.end()
 	return nil
 }
 
 func (t *tx) Rollback() error {
+	t.end()
 	return nil
 }
+
+func (t *tx) end() {
+	if t.conn != nil && t.conn.tx == t {
+		t.conn.tx = nil
+	}
+}
+
+type txCond struct{}
+
+func (tc txCond) matches(in *input) bool {
+	return in.tx != nil
+}
+
+func (tc txCond) priority() int {
+	return 1
+}
+
+func (tc txCond) String() string {
+	return "IN TX"
+}
+
+func (tc txCond) explain(in *input) string {
+	return "no transaction"
+}
+
+type isolationCond struct {
+	level sql.IsolationLevel
+}
+
+func (ic isolationCond) matches(in *input) bool {
+	if in.tx == nil {
+		return false
+	}
+	return sql.IsolationLevel(in.tx.Isolation) == ic.level
+}
+
+func (ic isolationCond) priority() int {
+	return 1
+}
+
+func (ic isolationCond) String() string {
+	return fmt.Sprintf("ISOLATION %s", ic.level)
+}
+
+func (ic isolationCond) explain(in *input) string {
+	if in.tx == nil {
+		return "no transaction"
+	}
+	return sql.IsolationLevel(in.tx.Isolation).String()
+}
+
+type readOnlyCond struct {
+	readOnly bool
+}
+
+func (rc readOnlyCond) matches(in *input) bool {
+	if in.tx == nil {
+		return false
+	}
+	return in.tx.ReadOnly == rc.readOnly
+}
+
+func (rc readOnlyCond) priority() int {
+	return 1
+}
+
+func (rc readOnlyCond) String() string {
+	if rc.readOnly {
+		return "READ ONLY"
+	}
+	return "READ WRITE"
+}
+
+func (rc readOnlyCond) explain(in *input) string {
+	switch {
+	case in.tx == nil:
+		return "no transaction"
+	case in.tx.ReadOnly:
+		return "read only"
+	}
+	return "read write"
+}
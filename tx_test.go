@@ -0,0 +1,101 @@
+package mogi_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/guregu/mogi"
+)
+
+func TestTx(t *testing.T) {
+	defer mogi.Reset()
+	db := openDB()
+
+	mogi.Update().Table("beer").InTx().StubRowsAffected(1)
+
+	// not in a transaction
+	_, err := db.Exec("UPDATE beer SET pct = ? WHERE id = ?", 4.7, 3)
+	if !errors.Is(err, mogi.ErrUnstubbed) {
+		t.Error("err should be ErrUnstubbed but is", err)
+	}
+
+	tx, err := db.Begin()
+	checkNil(t, err)
+	_, err = tx.Exec("UPDATE beer SET pct = ? WHERE id = ?", 4.7, 3)
+	checkNil(t, err)
+	checkNil(t, tx.Commit())
+
+	// transaction is over
+	_, err = db.Exec("UPDATE beer SET pct = ? WHERE id = ?", 4.7, 3)
+	if !errors.Is(err, mogi.ErrUnstubbed) {
+		t.Error("err should be ErrUnstubbed but is", err)
+	}
+}
+
+func TestTxOptions(t *testing.T) {
+	defer mogi.Reset()
+	db := openDB()
+	ctx := context.Background()
+
+	mogi.Select().From("beer").Isolation(sql.LevelSerializable).ReadOnly(true).StubCSV(beerCSV)
+
+	tx, err := db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelSerializable, ReadOnly: true})
+	checkNil(t, err)
+	rows, err := tx.QueryContext(ctx, "SELECT id, name, brewery, pct FROM beer WHERE pct > ?", 5)
+	checkNil(t, err)
+	rows.Close()
+	checkNil(t, tx.Rollback())
+
+	tx, err = db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelReadCommitted, ReadOnly: true})
+	checkNil(t, err)
+	_, err = tx.QueryContext(ctx, "SELECT id, name, brewery, pct FROM beer WHERE pct > ?", 5)
+	if !errors.Is(err, mogi.ErrUnstubbed) {
+		t.Error("err should be ErrUnstubbed but is", err)
+	}
+	checkNil(t, tx.Rollback())
+
+	tx, err = db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelSerializable})
+	checkNil(t, err)
+	_, err = tx.QueryContext(ctx, "SELECT id, name, brewery, pct FROM beer WHERE pct > ?", 5)
+	if !errors.Is(err, mogi.ErrUnstubbed) {
+		t.Error("err should be ErrUnstubbed but is", err)
+	}
+	checkNil(t, tx.Rollback())
+}
+
+func TestContextCanceled(t *testing.T) {
+	defer mogi.Reset()
+	db := openDB()
+	mogi.Select().StubCSV(beerCSV)
+	mogi.Insert().StubResult(1, 1)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	checkNil(t, db.PingContext(ctx))
+	cancel()
+
+	_, err := db.QueryContext(ctx, "SELECT id, name, brewery, pct FROM beer WHERE pct > ?", 5)
+	if !errors.Is(err, context.Canceled) {
+		t.Error("err should be context.Canceled but is", err)
+	}
+	_, err = db.ExecContext(ctx, "INSERT INTO beer (name, brewery, pct) VALUES (?, ?, ?)", "Mikkel’s Dream", "Mikkeller", 4.6)
+	if !errors.Is(err, context.Canceled) {
+		t.Error("err should be context.Canceled but is", err)
+	}
+	if err := db.PingContext(ctx); !errors.Is(err, context.Canceled) {
+		t.Error("err should be context.Canceled but is", err)
+	}
+
+	// prepared statements
+	stmt, err := db.Prepare("SELECT id, name, brewery, pct FROM beer WHERE pct > ?")
+	checkNil(t, err)
+	defer stmt.Close()
+	_, err = stmt.QueryContext(ctx, 5)
+	if !errors.Is(err, context.Canceled) {
+		t.Error("err should be context.Canceled but is", err)
+	}
+	rows, err := stmt.QueryContext(context.Background(), 5)
+	checkNil(t, err)
+	rows.Close()
+}
@@ -90,18 +90,22 @@ func reflectUnify(rv reflect.Value) interface{} {
 	panic("couldn't unify value of type " + rv.Type().Name())
 }
 
+// unifyValues returns a unified copy of values.
 func unifyValues(values []driver.Value) []driver.Value {
+	unified := make([]driver.Value, len(values))
 	for i, v := range values {
-		values[i] = unify(v)
+		unified[i] = unify(v)
 	}
-	return values
+	return unified
 }
 
+// unifyInterfaces returns a unified copy of slice.
 func unifyInterfaces(slice []interface{}) []interface{} {
+	unified := make([]interface{}, len(slice))
 	for i, v := range slice {
-		slice[i] = unify(v)
+		unified[i] = unify(v)
 	}
-	return slice
+	return unified
 }
 
 func stringify(v interface{}) string {
@@ -119,6 +123,7 @@ func lowercase(strs []string) []string {
 func equals(src interface{}, to interface{}) bool {
 	switch tox := to.(type) {
 	case time.Time:
+		timeLayout := parseTimeLayout()
 		// we need to convert source timestamps to time.Time
 		if timeLayout == "" {
 			break
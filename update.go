@@ -12,7 +12,7 @@ type updateCond struct {
 	cols []string
 }
 
-func (uc updateCond) matches(in input) bool {
+func (uc updateCond) matches(in *input) bool {
 	_, ok := in.statement.(*sqlparser.Update)
 	if !ok {
 		return false
@@ -1,6 +1,7 @@
 package mogi_test
 
 import (
+	"errors"
 	"testing"
 	"time"
 
@@ -32,7 +33,7 @@ func TestUpdate(t *testing.T) {
 	_, err = db.Exec(`UPDATE beer
 					   SET name = "Mikkel’s Dream", brewery = "Mikkeller", pct = 4.6
 					   WHERE id = 3`)
-	if err != mogi.ErrUnstubbed {
+	if !errors.Is(err, mogi.ErrUnstubbed) {
 		t.Error("err should be ErrUnstubbed but is", err)
 	}
 }
@@ -54,7 +55,7 @@ func TestUpdateTable(t *testing.T) {
 	_, err = db.Exec(`UPDATE beer
 					   SET name = "Mikkel’s Dream", brewery = "Mikkeller", pct = 4.6
 					   WHERE id = 3`)
-	if err != mogi.ErrUnstubbed {
+	if !errors.Is(err, mogi.ErrUnstubbed) {
 		t.Error("err should be ErrUnstubbed but is", err)
 	}
 }
@@ -93,7 +94,7 @@ func TestUpdateValues(t *testing.T) {
 	_, err = db.Exec(`UPDATE beer
 					   SET name = "Mikkel’s Dream", brewery = "Mikkeller", pct = ?
 					   WHERE id = 3`, 4.6)
-	if err != mogi.ErrUnstubbed {
+	if !errors.Is(err, mogi.ErrUnstubbed) {
 		t.Error("err should be ErrUnstubbed but is", err)
 	}
 }
@@ -113,7 +114,7 @@ func TestUpdateWhere(t *testing.T) {
 	_, err = db.Exec(`UPDATE beer
 					   SET name = "Mikkel’s Dream", brewery = "Mikkeller", pct = ?
 					   WHERE id = 3 AND moon = "full"`, 4.6)
-	if err != mogi.ErrUnstubbed {
+	if !errors.Is(err, mogi.ErrUnstubbed) {
 		t.Error("err should be ErrUnstubbed but is", err)
 	}
 }
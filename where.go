@@ -1,8 +1,13 @@
 package mogi
 
 import (
+	"database/sql/driver"
 	"fmt"
+	"sort"
 	"strings"
+	"time"
+
+	"github.com/guregu/mogi/internal/sqlparser"
 )
 
 type whereCond struct {
@@ -17,7 +22,7 @@ func newWhereCond(col string, v []interface{}) whereCond {
 	}
 }
 
-func (wc whereCond) matches(in input) bool {
+func (wc whereCond) matches(in *input) bool {
 	vals := in.where()
 	v, ok := vals[wc.col]
 	if !ok {
@@ -26,6 +31,9 @@ func (wc whereCond) matches(in input) bool {
 
 	// compare slices
 	if slice, ok := v.([]interface{}); ok {
+		if len(slice) != len(wc.v) {
+			return false
+		}
 		for i, src := range slice {
 			if !equals(src, wc.v[i]) {
 				return false
@@ -60,15 +68,22 @@ func newWhereOpCond(col string, v []interface{}, op string) whereOpCond {
 	}
 }
 
-func (wc whereOpCond) matches(in input) bool {
+func (wc whereOpCond) matches(in *input) bool {
 	vals := in.whereOp()
 	v, ok := vals[colop{wc.col, wc.op}]
 	if !ok {
 		return false
 	}
+	// operators without values, like IS NULL
+	if len(wc.v) == 0 {
+		return true
+	}
 
 	// compare slices
 	if slice, ok := v.([]interface{}); ok {
+		if len(slice) != len(wc.v) {
+			return false
+		}
 		for i, src := range slice {
 			if !equals(src, wc.v[i]) {
 				return false
@@ -86,5 +101,191 @@ func (wc whereOpCond) priority() int {
 }
 
 func (wc whereOpCond) String() string {
+	if len(wc.v) == 0 {
+		return fmt.Sprintf("WHERE %s %s", wc.col, strings.ToUpper(wc.op))
+	}
 	return fmt.Sprintf("WHERE %s %s %v", wc.col, strings.ToUpper(wc.op), wc.v)
 }
+
+type whereExprCond struct {
+	expr  string
+	canon string
+	err   error // if expr couldn't be parsed
+}
+
+func newWhereExprCond(dialect Dialect, expr string, args []interface{}) whereExprCond {
+	stmt, err := sqlparser.Parse(dialect.translate("SELECT 1 FROM t WHERE " + expr))
+	if err != nil {
+		return whereExprCond{expr: expr, err: fmt.Errorf("mogi: bad WHERE expression %q: %w", expr, err)}
+	}
+	sel, ok := stmt.(*sqlparser.Select)
+	if !ok || sel.Where == nil || sel.GroupBy != nil || sel.Having != nil || sel.OrderBy != nil || sel.Limit != nil || sel.Lock != "" {
+		return whereExprCond{expr: expr, err: fmt.Errorf("mogi: bad WHERE expression %q: not a single expression", expr)}
+	}
+	in := &input{args: make([]driver.Value, len(args))}
+	for i, arg := range args {
+		in.args[i] = arg
+	}
+	return whereExprCond{
+		expr:  expr,
+		canon: canonicalWhere(in, sel.Where.Expr),
+	}
+}
+
+func (wc whereExprCond) matches(in *input) bool {
+	if wc.err != nil {
+		return false
+	}
+	w, _ := in.whereClause()
+	if w == nil {
+		return false
+	}
+	return canonicalWhere(in, w.Expr) == wc.canon
+}
+
+func (wc whereExprCond) invalid() error {
+	return wc.err
+}
+
+func (wc whereExprCond) priority() int {
+	return 3
+}
+
+func (wc whereExprCond) String() string {
+	return fmt.Sprintf("WHERE %s", wc.expr)
+}
+
+type whereRowCond struct {
+	cols []string
+	row  []driver.Value
+}
+
+func newWhereRowCond(row map[string]interface{}) whereRowCond {
+	wc := whereRowCond{
+		cols: make([]string, 0, len(row)),
+	}
+	for col := range row {
+		wc.cols = append(wc.cols, col)
+	}
+	sort.Strings(wc.cols)
+	for _, col := range wc.cols {
+		wc.row = append(wc.row, unify(row[col]))
+	}
+	return wc
+}
+
+func (wc whereRowCond) matches(in *input) bool {
+	ok, err := in.satisfies(wc.cols, wc.row)
+	return ok && err == nil
+}
+
+func (wc whereRowCond) priority() int {
+	return 1
+}
+
+func (wc whereRowCond) String() string {
+	pairs := make([]string, len(wc.cols))
+	for i, col := range wc.cols {
+		pairs[i] = fmt.Sprintf("%s = %v", col, wc.row[i])
+	}
+	return fmt.Sprintf("WHERE SATISFIED BY (%s)", strings.Join(pairs, ", "))
+}
+
+// canonicalWhere returns a normalized form of a WHERE clause, for comparing logical structure.
+// Placeholders are replaced by their values, parentheses are dropped,
+// and the operands of AND and OR are sorted.
+func canonicalWhere(in *input, expr sqlparser.BoolExpr) string {
+	switch x := expr.(type) {
+	case *sqlparser.AndExpr:
+		return canonicalJunction(in, "AND", flattenJunction(x, true, nil))
+	case *sqlparser.OrExpr:
+		return canonicalJunction(in, "OR", flattenJunction(x, false, nil))
+	case *sqlparser.ParenBoolExpr:
+		return canonicalWhere(in, x.Expr)
+	case *sqlparser.NotExpr:
+		return "NOT " + canonicalWhere(in, x.Expr)
+	case *sqlparser.ComparisonExpr:
+		return fmt.Sprintf("%s %s %s", canonicalValue(in, x.Left), strings.ToUpper(x.Operator), canonicalValue(in, x.Right))
+	case *sqlparser.RangeCond:
+		return fmt.Sprintf("%s %s %s AND %s", canonicalValue(in, x.Left), strings.ToUpper(x.Operator),
+			canonicalValue(in, x.From), canonicalValue(in, x.To))
+	case *sqlparser.IsExpr:
+		if bexpr, ok := x.Expr.(sqlparser.BoolExpr); ok {
+			return fmt.Sprintf("(%s) %s", canonicalWhere(in, bexpr), strings.ToUpper(x.Operator))
+		}
+		return fmt.Sprintf("%s %s", canonicalValue(in, x.Expr), strings.ToUpper(x.Operator))
+	case sqlparser.BoolVal:
+		return canonicalValue(in, x)
+	}
+	return sqlparser.String(expr)
+}
+
+func canonicalJunction(in *input, op string, exprs []sqlparser.BoolExpr) string {
+	strs := make([]string, len(exprs))
+	for i, expr := range exprs {
+		strs[i] = canonicalWhere(in, expr)
+	}
+	sort.Strings(strs)
+	return "(" + strings.Join(strs, " "+op+" ") + ")"
+}
+
+// flattenJunction returns the operands of a chain of ANDs (or ORs), such as a, b, and c for a AND (b AND c).
+func flattenJunction(expr sqlparser.BoolExpr, and bool, operands []sqlparser.BoolExpr) []sqlparser.BoolExpr {
+	for {
+		paren, ok := expr.(*sqlparser.ParenBoolExpr)
+		if !ok {
+			break
+		}
+		expr = paren.Expr
+	}
+	switch x := expr.(type) {
+	case *sqlparser.AndExpr:
+		if and {
+			return flattenJunction(x.Right, and, flattenJunction(x.Left, and, operands))
+		}
+	case *sqlparser.OrExpr:
+		if !and {
+			return flattenJunction(x.Right, and, flattenJunction(x.Left, and, operands))
+		}
+	}
+	return append(operands, expr)
+}
+
+// canonicalValue returns a normalized form of a value expression.
+// Columns are lowercase and values are formatted like Go values, so 'abc' is "abc" and 1.0 is 1.
+func canonicalValue(in *input, expr sqlparser.Expr) string {
+	switch x := expr.(type) {
+	case *sqlparser.ColName:
+		if x.Qualifier == "" && strings.HasPrefix(string(x.Name), "@") {
+			break
+		}
+		return strings.ToLower(stringify(transmogrify(x)))
+	case sqlparser.ValTuple:
+		if len(x) == 1 {
+			return canonicalValue(in, x[0])
+		}
+		strs := make([]string, len(x))
+		for i, item := range x {
+			strs[i] = canonicalValue(in, item)
+		}
+		return "(" + strings.Join(strs, ", ") + ")"
+	case *sqlparser.BinaryExpr:
+		return fmt.Sprintf("(%s %s %s)", canonicalValue(in, x.Left), x.Operator, canonicalValue(in, x.Right))
+	case sqlparser.ValArg, sqlparser.StrVal, sqlparser.NumVal, *sqlparser.NullVal, sqlparser.BoolVal:
+	default:
+		return sqlparser.String(expr)
+	}
+
+	v, err := evaluator{in: in}.value(expr)
+	if err != nil {
+		return sqlparser.String(expr)
+	}
+	switch x := normalize(v).(type) {
+	case nil:
+		return "NULL"
+	case time.Time:
+		return fmt.Sprintf("%q", x.Format(time.RFC3339Nano))
+	default:
+		return fmt.Sprintf("%#v", x)
+	}
+}
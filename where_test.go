@@ -0,0 +1,153 @@
+package mogi_test
+
+import (
+	"database/sql/driver"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/guregu/mogi"
+)
+
+func TestWhereExpr(t *testing.T) {
+	defer mogi.Reset()
+	db := openDB()
+
+	mogi.Select().From("beer").WhereExpr("brewery = 'BrewDog' OR (pct > ? AND name LIKE 'Yona%')", 5).StubCSV(beerCSV)
+
+	// same structure, different order and parentheses
+	_, err := db.Query("SELECT id, name, brewery, pct FROM beer WHERE (name LIKE ? AND pct > ?) OR brewery = ?", "Yona%", 5, "BrewDog")
+	checkNil(t, err)
+	_, err = db.Query("SELECT id, name, brewery, pct FROM beer WHERE brewery = \"BrewDog\" OR ((pct > 5.0) AND name LIKE 'Yona%')")
+	checkNil(t, err)
+
+	// AND is not OR
+	_, err = db.Query("SELECT id, name, brewery, pct FROM beer WHERE brewery = ? AND (pct > ? AND name LIKE ?)", "BrewDog", 5, "Yona%")
+	if !errors.Is(err, mogi.ErrUnstubbed) {
+		t.Error("err should be ErrUnstubbed but is", err)
+	}
+	// different values
+	_, err = db.Query("SELECT id, name, brewery, pct FROM beer WHERE brewery = ? OR (pct > ? AND name LIKE ?)", "BrewDog", 6, "Yona%")
+	if !errors.Is(err, mogi.ErrUnstubbed) {
+		t.Error("err should be ErrUnstubbed but is", err)
+	}
+
+	mogi.Reset()
+	mogi.Delete().Table("beer").WhereExpr("id BETWEEN 1 AND 3 AND deleted_at IS NULL").StubRowsAffected(3)
+	res, err := db.Exec("DELETE FROM beer WHERE deleted_at IS NULL AND id BETWEEN ? AND ?", 1, 3)
+	checkNil(t, err)
+	checkRowsAffected(t, res, 3)
+}
+
+func TestWhereRow(t *testing.T) {
+	defer mogi.Reset()
+	db := openDB()
+
+	mogi.Select().From("beer").WhereRow(map[string]interface{}{
+		"id":      2,
+		"brewery": "BrewDog",
+		"pct":     5.6,
+	}).StubCSV(`2,Punk IPA,BrewDog,5.6`)
+
+	queries := []struct {
+		where string
+		args  []interface{}
+		ok    bool
+	}{
+		{"id = ?", []interface{}{2}, true},
+		{"id = ?", []interface{}{3}, false},
+		{"id IN (1, 2, 3) AND brewery = 'BrewDog'", nil, true},
+		{"id = 1 OR brewery LIKE 'brew%'", nil, true},
+		{"NOT (id = 2)", nil, false},
+		{"pct BETWEEN 5 AND 6", nil, true},
+		{"pct NOT BETWEEN 5 AND 6", nil, false},
+		{"brewery IS NOT NULL", nil, true},
+		{"brewery IS NULL OR id > 1", nil, true},
+		{"id != 2 OR brewery REGEXP '^brew'", nil, true},
+		// unknown column
+		{"name = 'Punk IPA'", nil, false},
+	}
+	for _, q := range queries {
+		_, err := db.Query("SELECT id, name, brewery, pct FROM beer WHERE "+q.where, q.args...)
+		if q.ok && err != nil {
+			t.Error(q.where, "should match but got", err)
+		}
+		if !q.ok && !errors.Is(err, mogi.ErrUnstubbed) {
+			t.Error(q.where, "should be unstubbed but got", err)
+		}
+	}
+}
+
+func TestTableWhere(t *testing.T) {
+	defer mogi.Reset()
+	db := openDB()
+
+	mogi.Table("beer", "id", "name", "brewery", "pct").SeedRows([][]driver.Value{
+		{1, "Yona Yona Ale", "Yo-Ho Brewing", 5.5},
+		{2, "Punk IPA", "BrewDog", 5.6},
+		{3, "Mikkel’s Dream", "Mikkeller", nil},
+	})
+
+	checkTablePcts(t, db, "SELECT pct FROM beer WHERE pct BETWEEN 5 AND 5.5", nil, 5.5)
+	checkTablePcts(t, db, "SELECT id FROM beer WHERE pct IS NULL", nil, 3)
+	checkTablePcts(t, db, "SELECT pct FROM beer WHERE name LIKE '%ipa' OR (brewery = 'Mikkeller' AND pct IS NOT NULL)", nil, 5.6)
+	// NOT NULL is still NULL
+	checkTablePcts(t, db, "SELECT pct FROM beer WHERE NOT pct > 5.5", nil, 5.5)
+}
+
+func TestWhereOps(t *testing.T) {
+	defer mogi.Reset()
+	db := openDB()
+
+	mogi.Select().From("beer").WhereNull("deleted_at").WhereBetween("pct", 5, 6).StubCSV(beerCSV)
+	_, err := db.Query("SELECT id, name, brewery, pct FROM beer WHERE deleted_at IS NULL AND pct BETWEEN ? AND ?", 5, 6)
+	checkNil(t, err)
+	_, err = db.Query("SELECT id, name, brewery, pct FROM beer WHERE deleted_at IS NOT NULL AND pct BETWEEN ? AND ?", 5, 6)
+	if !errors.Is(err, mogi.ErrUnstubbed) {
+		t.Error("err should be ErrUnstubbed but is", err)
+	}
+	_, err = db.Query("SELECT id, name, brewery, pct FROM beer WHERE deleted_at IS NULL AND pct BETWEEN ? AND ?", 5, 7)
+	if !errors.Is(err, mogi.ErrUnstubbed) {
+		t.Error("err should be ErrUnstubbed but is", err)
+	}
+
+	mogi.Reset()
+	mogi.Update().Table("beer").WhereNotNull("deleted_at").WhereLike("name", "%IPA").WhereNotIn("id", 1, 2).StubRowsAffected(1)
+	res, err := db.Exec("UPDATE beer SET pct = 0 WHERE deleted_at IS NOT NULL AND name LIKE ? AND id NOT IN (?, ?)", "%IPA", 1, 2)
+	checkNil(t, err)
+	checkRowsAffected(t, res, 1)
+	_, err = db.Exec("UPDATE beer SET pct = 0 WHERE deleted_at IS NOT NULL AND name LIKE ? AND id IN (?, ?)", "%IPA", 1, 2)
+	if !errors.Is(err, mogi.ErrUnstubbed) {
+		t.Error("err should be ErrUnstubbed but is", err)
+	}
+	_, err = db.Exec("UPDATE beer SET pct = 0 WHERE deleted_at IS NOT NULL AND name LIKE ? AND id NOT IN (?, ?, ?)", "%IPA", 1, 2, 3)
+	if !errors.Is(err, mogi.ErrUnstubbed) {
+		t.Error("err should be ErrUnstubbed but is", err)
+	}
+}
+
+func TestWhereExprInvalid(t *testing.T) {
+	defer mogi.Reset()
+	db := openDB()
+
+	mogi.Select().From("beer").WhereExpr("brewery = = 'BrewDog'").StubCSV(beerCSV)
+	mogi.Select().From("wine").StubCSV(beerCSV)
+
+	_, err := db.Query("SELECT id, name, brewery, pct FROM beer WHERE brewery = 'BrewDog'")
+	if err == nil || !strings.Contains(err.Error(), "bad WHERE expression") {
+		t.Error("expected a bad WHERE expression error, got", err)
+	}
+	// other stubs still work
+	_, err = db.Query("SELECT id, name, brewery, pct FROM wine")
+	checkNil(t, err)
+
+	// more than an expression
+	for _, expr := range []string{"a = 1 UNION SELECT 1 FROM t", "a = 1 ORDER BY a"} {
+		mogi.Reset()
+		mogi.Select().From("beer").WhereExpr(expr).StubCSV(beerCSV)
+		_, err = db.Query("SELECT id, name, brewery, pct FROM beer WHERE a = 1")
+		if err == nil || !strings.Contains(err.Error(), "bad WHERE expression") {
+			t.Error(expr, "expected a bad WHERE expression error, got", err)
+		}
+	}
+}